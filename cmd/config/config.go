@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
 	"sort"
 
 	"github.com/spf13/cobra"
@@ -12,56 +13,78 @@ var (
 	cfg *config.Config
 )
 
+type configDocument struct {
+	Values      map[string]any `json:"values,omitempty" yaml:"values,omitempty"`
+	ConfigPaths []string       `json:"configPaths,omitempty" yaml:"configPaths,omitempty"`
+	Action      string         `json:"action,omitempty" yaml:"action,omitempty"`
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage kody configuration",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
 		if len(args) == 0 {
-			getAllConfig()
-			return nil
+			return getAllConfig(out)
 		}
 
 		if len(args) == 1 {
 			value := cfg.Get(args[0])
 			if value != nil {
-				fmt.Printf("%s: %v\n", args[0], value)
-				return nil
+				out.Printf("%s: %v\n", args[0], value)
 			}
+			return out.Document(configDocument{Values: map[string]any{args[0]: value}})
 		}
 
 		if len(args) == 2 {
 			cfg.Set(args[0], args[1])
 			err := cfg.Write()
 			if err != nil {
-				return fmt.Errorf("writing config: %w", err)
+				return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("writing config: %w", err))
 			}
+			return out.Document(configDocument{Values: map[string]any{args[0]: args[1]}, Action: "set"})
 		}
 
 		return nil
 	},
 }
 
-func getAllConfig() {
+func getAllConfig(out *output.Printer) error {
 	keys := cfg.AllKeys()
 	sort.Strings(keys)
 
+	values := make(map[string]any, len(keys))
+	for _, key := range keys {
+		value := cfg.Get(key)
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	paths, pathsErr := cfg.ConfigPaths()
+
+	if !out.IsText() {
+		return out.Document(configDocument{Values: values, ConfigPaths: paths})
+	}
+
 	if len(keys) == 0 {
-		fmt.Println("No configurations defined yet.")
-		return
+		out.Println("No configurations defined yet.")
+		return nil
 	}
 
 	for _, key := range keys {
-		value := cfg.Get(key)
-		if value != nil {
-			fmt.Printf("%s: %v\n", key, value)
+		if value, ok := values[key]; ok {
+			out.Printf("%s: %v\n", key, value)
 		}
 	}
 
-	paths, err := cfg.ConfigPaths()
-	if err != nil {
-		fmt.Printf("\n(Error getting config paths: %v)\n", err)
+	if pathsErr != nil {
+		out.Printf("\n(Error getting config paths: %v)\n", pathsErr)
 	}
-	fmt.Printf("\n(config paths: %v)\n", paths)
+	out.Printf("\n(config paths: %v)\n", paths)
+
+	return nil
 }
 
 func GetCmd(config *config.Config) *cobra.Command {
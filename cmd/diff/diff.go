@@ -0,0 +1,207 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	filediff "github.com/andrerfcsantos/kody/lib/diff"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop *workshop.Workshop
+	outputDir       string
+	statOnly        bool
+	colorMode       string
+	contextLines    int
+)
+
+type diffDocument struct {
+	Workshop   *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Exercise   *output.ExerciseInfo `json:"exercise" yaml:"exercise"`
+	Saved      string               `json:"saved" yaml:"saved"`
+	Playground string               `json:"playground" yaml:"playground"`
+	Files      []fileChange         `json:"files" yaml:"files"`
+	Inserted   int                  `json:"inserted" yaml:"inserted"`
+	Deleted    int                  `json:"deleted" yaml:"deleted"`
+}
+
+type fileChange struct {
+	Path     string          `json:"path" yaml:"path"`
+	Status   filediff.Status `json:"status" yaml:"status"`
+	Binary   bool            `json:"binary" yaml:"binary"`
+	Inserted int             `json:"inserted" yaml:"inserted"`
+	Deleted  int             `json:"deleted" yaml:"deleted"`
+	Patch    string          `json:"patch,omitempty" yaml:"patch,omitempty"`
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what changed in the playground since the exercise was saved",
+	Long: `Show the differences between your saved solution of the exercise in the playground and the playground, as a unified diff. Lines starting with - are only in the saved solution, lines starting with + only in the playground.
+
+Run it before kody save to see what you are about to save, or before kody restore to see what you would lose. With --stat, only the number of changed lines of each file is shown.`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkAndSetupConfigs(cmd); err != nil {
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: %w", err))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		exercise, err := w.PlaygroundExercise()
+		if err != nil {
+			return output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("getting playground exercise: %w", err))
+		}
+
+		savedPath, err := solutions.FindSaved(outputDir, w, exercise.Section.Number, exercise.Number)
+		if errors.Is(err, solutions.ErrNotSaved) {
+			return output.WithCode(output.CodeSavedExerciseMissing, err)
+		}
+		if err != nil {
+			return err
+		}
+
+		diffs, err := filediff.Dirs(savedPath, w.PlaygroundPath(), workshop.IsDependencyOrCacheDir)
+		if err != nil {
+			return fmt.Errorf("comparing playground with saved solution: %w", err)
+		}
+
+		formatOpts := filediff.FormatOptions{
+			OldLabel: "saved",
+			NewLabel: "playground",
+			Context:  contextLines,
+			Color:    useColor(out),
+		}
+
+		doc := diffDocument{
+			Workshop:   output.NewWorkshopInfo(w),
+			Exercise:   output.NewExerciseInfo(exercise),
+			Saved:      savedPath,
+			Playground: w.PlaygroundPath(),
+			Files:      []fileChange{},
+		}
+		for _, fileDiff := range diffs {
+			change := fileChange{
+				Path:     fileDiff.Path,
+				Status:   fileDiff.Status,
+				Binary:   fileDiff.Binary,
+				Inserted: fileDiff.Inserted,
+				Deleted:  fileDiff.Deleted,
+			}
+			if !statOnly {
+				patchOpts := formatOpts
+				patchOpts.Color = false
+				change.Patch = filediff.Unified(fileDiff, patchOpts)
+			}
+			doc.Files = append(doc.Files, change)
+			doc.Inserted += fileDiff.Inserted
+			doc.Deleted += fileDiff.Deleted
+		}
+
+		if len(diffs) == 0 {
+			out.Printf("The playground has the same files as your saved solution of %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
+			return out.Document(doc)
+		}
+
+		if statOnly {
+			stat := &strings.Builder{}
+			if err := filediff.WriteStat(stat, diffs, formatOpts); err != nil {
+				return err
+			}
+			out.Print(stat.String())
+		} else {
+			for _, fileDiff := range diffs {
+				out.Print(filediff.Unified(fileDiff, formatOpts))
+			}
+		}
+
+		return out.Document(doc)
+	},
+}
+
+// useColor tells if the diff should be colored, following --color. In auto mode, the diff is colored when
+// it is written to a terminal, unless the NO_COLOR environment variable is set.
+func useColor(out *output.Printer) bool {
+	switch strings.ToLower(colorMode) {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if !out.IsText() || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+
+	switch strings.ToLower(colorMode) {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid --color '%s', must be one of: auto, always, never", colorMode)
+	}
+
+	if contextLines < 0 {
+		return fmt.Errorf("invalid --unified %d, the number of context lines can't be negative", contextLines)
+	}
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+
+	if outputDir == "" {
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
+	}
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", diffCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", diffCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", diffCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", diffCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", diffCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", diffCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", diffCmd)
+
+	diffCmd.Flags().BoolVar(&statOnly, "stat", false, "Only show the number of changed lines of each file")
+	diffCmd.Flags().StringVar(&colorMode, "color", "auto", "When to color the diff: auto, always or never")
+	diffCmd.Flags().IntVarP(&contextLines, "unified", "U", 3, "Number of unchanged lines to show around the changes")
+
+	return diffCmd
+}
@@ -0,0 +1,214 @@
+package exercises
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop *workshop.Workshop
+	workshopSource  workshop.ResolutionSource
+	outputDir       string
+	sectionFilters  []string
+)
+
+type exercisesDocument struct {
+	Workshop *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Sections []sectionInfo        `json:"sections" yaml:"sections"`
+}
+
+type sectionInfo struct {
+	Number    int            `json:"number" yaml:"number"`
+	Slug      string         `json:"slug" yaml:"slug"`
+	Title     string         `json:"title" yaml:"title"`
+	Exercises []exerciseInfo `json:"exercises" yaml:"exercises"`
+}
+
+type exerciseInfo struct {
+	Number   int        `json:"number" yaml:"number"`
+	Slug     string     `json:"slug" yaml:"slug"`
+	Title    string     `json:"title" yaml:"title"`
+	Current  bool       `json:"current" yaml:"current"`
+	SavedAt  *time.Time `json:"savedAt,omitempty" yaml:"savedAt,omitempty"`
+	HasNotes bool       `json:"hasNotes" yaml:"hasNotes"`
+}
+
+var exercisesCmd = &cobra.Command{
+	Use:   "exercises",
+	Short: "List the sections and exercises of the workshop",
+	Long:  `List the sections and exercises of the current workshop as a tree, with their titles. Exercises are marked when they are in the playground (>), have a saved solution (x) or have notes (+).`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.WithCode(output.CodeWorkshopNotFound, checkAndSetupConfigs(cmd))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		var current *workshop.Exercise
+		if w.HasPlayground() {
+			current, err = w.PlaygroundExercise()
+			if err != nil && !errors.Is(err, workshop.ErrNoExactMatch) {
+				return fmt.Errorf("getting playground exercise: %w", err)
+			}
+		}
+
+		outline, err := w.Outline()
+		if err != nil {
+			return fmt.Errorf("loading workshop: %w", err)
+		}
+
+		doc := exercisesDocument{
+			Workshop: output.NewWorkshopInfo(w),
+			Sections: []sectionInfo{},
+		}
+
+		for _, section := range outline.Sections {
+			if !matchesSectionFilters(section.Section) {
+				continue
+			}
+
+			info := sectionInfo{
+				Number:    section.Number,
+				Slug:      section.Slug,
+				Title:     section.Title(),
+				Exercises: []exerciseInfo{},
+			}
+
+			for _, exercise := range section.Exercises {
+				savedAt, err := workshop.SavedExerciseTime(outputDir, w, exercise.Exercise)
+				if err != nil {
+					return fmt.Errorf("checking saved solution for exercise %s: %w", exercise.BreadCrumbs(), err)
+				}
+
+				notes, err := workshop.SavedExerciseNotes(outputDir, w, exercise.Exercise)
+				if err != nil {
+					return fmt.Errorf("checking notes for exercise %s: %w", exercise.BreadCrumbs(), err)
+				}
+
+				info.Exercises = append(info.Exercises, exerciseInfo{
+					Number:   exercise.Number,
+					Slug:     exercise.Slug,
+					Title:    exercise.Title(),
+					Current:  current != nil && outline.Find(current) == exercise,
+					SavedAt:  savedAt,
+					HasNotes: notes != "",
+				})
+			}
+
+			doc.Sections = append(doc.Sections, info)
+		}
+
+		printTree(out, w, doc)
+		return out.Document(doc)
+	},
+}
+
+func matchesSectionFilters(section workshop.Section) bool {
+	if len(sectionFilters) == 0 {
+		return true
+	}
+
+	for _, filter := range sectionFilters {
+		if number, err := strconv.Atoi(filter); err == nil && number == section.Number {
+			return true
+		}
+		if filter == section.Slug {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printTree(out *output.Printer, w *workshop.Workshop, doc exercisesDocument) {
+	out.Printf("%s (%s)\n", w.AsciiTitle(), w.Slug())
+
+	if len(doc.Sections) == 0 {
+		out.Println("No exercises found.")
+		return
+	}
+
+	for i, section := range doc.Sections {
+		sectionBranch, exercisePrefix := "├──", "│   "
+		if i == len(doc.Sections)-1 {
+			sectionBranch, exercisePrefix = "└──", "    "
+		}
+
+		out.Printf("%s [%0.2d] %s\n", sectionBranch, section.Number, section.Title)
+
+		for j, exercise := range section.Exercises {
+			exerciseBranch := "├──"
+			if j == len(section.Exercises)-1 {
+				exerciseBranch = "└──"
+			}
+
+			out.Printf("%s%s %s [%0.2d] %s\n", exercisePrefix, exerciseBranch, markers(exercise), exercise.Number, exercise.Title)
+		}
+	}
+}
+
+func markers(exercise exerciseInfo) string {
+	current, saved, notes := " ", " ", " "
+	if exercise.Current {
+		current = ">"
+	}
+	if exercise.SavedAt != nil {
+		saved = "x"
+	}
+	if exercise.HasNotes {
+		notes = "+"
+	}
+	return current + saved + notes
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+	workshopSource = resolution.Source
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", exercisesCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", exercisesCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", exercisesCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", exercisesCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", exercisesCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", exercisesCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", exercisesCmd)
+
+	exercisesCmd.Flags().StringSliceVarP(&sectionFilters, "section", "s", nil, "Only list the sections with these numbers or slugs")
+
+	return exercisesCmd
+}
@@ -0,0 +1,150 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+const timeFormat = "2006-01-02 15:04:05"
+
+var (
+	currentWorkshop *workshop.Workshop
+	limit           int
+	kinds           []string
+)
+
+type historyDocument struct {
+	Workshop *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Events   []progress.Event     `json:"events" yaml:"events"`
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [exercise]",
+	Short: "Show the activity recorded for the exercises of the workshop",
+	Long:  `Show the activity kody recorded for the exercises of the current workshop: exercises detected in the playground, edits to the playground, saves, restores and test runs. Pass an exercise in the format <section_number>.<exercise_number>, e.g. "01.02", to only show its activity.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.WithCode(output.CodeWorkshopNotFound, checkAndSetupConfigs(cmd))
+	},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return errors.New("history accepts at most one argument with the exercise, in the format <section_number>.<exercise_number>, e.g. \"01.02\"")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		query := progress.Query{Workshop: w.Slug()}
+		if len(args) == 1 {
+			exercise, err := parseExerciseKey(args[0])
+			if err != nil {
+				return err
+			}
+			query.Exercise = exercise
+		}
+		for _, kind := range kinds {
+			query.Kinds = append(query.Kinds, progress.Kind(kind))
+		}
+
+		db, err := progress.Open(config.DefaultProgressDBPath(cfg))
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		events, err := db.Events(query)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && len(events) > limit {
+			events = events[len(events)-limit:]
+		}
+
+		if len(events) == 0 {
+			out.Printf("No activity recorded for %s yet\n", w.Slug())
+		}
+		for _, event := range events {
+			out.Printf("%s  %-8s  %s%s\n", event.Time.Local().Format(timeFormat), event.Kind, event.BreadCrumbs, eventDetails(event))
+		}
+
+		return out.Document(historyDocument{
+			Workshop: output.NewWorkshopInfo(w),
+			Events:   append([]progress.Event{}, events...),
+		})
+	},
+}
+
+func eventDetails(event progress.Event) string {
+	switch {
+	case event.Summary != "":
+		return " (" + event.Summary + ")"
+	case event.Path != "":
+		return " (" + event.Path + ")"
+	}
+	return ""
+}
+
+// parseExerciseKey normalizes an exercise argument like "1.2" to the key used in the history, "01.02".
+func parseExerciseKey(arg string) (string, error) {
+	split := strings.Split(arg, ".")
+	if len(split) != 2 {
+		return "", errors.New("exercise argument must be in the format <section_number>.<exercise_number>, e.g. \"01.02\"")
+	}
+
+	section, err := strconv.Atoi(split[0])
+	if err != nil {
+		return "", fmt.Errorf("parsing section number: %w", err)
+	}
+	exercise, err := strconv.Atoi(split[1])
+	if err != nil {
+		return "", fmt.Errorf("parsing exercise number: %w", err)
+	}
+
+	return fmt.Sprintf("%02d.%02d", section, exercise), nil
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", historyCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", historyCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", historyCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", historyCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", historyCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", historyCmd)
+
+	historyCmd.Flags().IntVarP(&limit, "limit", "n", 50, "Only show the latest n events, 0 to show all of them")
+	historyCmd.Flags().StringSliceVarP(&kinds, "kind", "k", nil, "Only show events of these kinds: detected, edited, saved, restored or tested")
+
+	return historyCmd
+}
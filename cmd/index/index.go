@@ -0,0 +1,89 @@
+package index
+
+import (
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop *workshop.Workshop
+	workshopSource  workshop.ResolutionSource
+)
+
+type indexDocument struct {
+	Workshop *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Path     string               `json:"path" yaml:"path"`
+	Entries  int                  `json:"entries" yaml:"entries"`
+}
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the exercise hash index",
+	Long:  `Kody keeps an index per workshop with the hashes of the exercises README.mdx files, so the current exercise can be detected without hashing every exercise again. Entries are invalidated automatically when a README.mdx changes size or modification time.`,
+}
+
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild the exercise hash index of the current workshop",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkAndSetupConfigs(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		index, err := w.RebuildHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("rebuilding exercise hash index: %w", err)
+		}
+
+		out.Printf("Indexed %d exercises of workshop '%s' in '%s'\n", index.Len(), w.Slug(), index.Path())
+		return out.Document(indexDocument{
+			Workshop: output.NewWorkshopInfo(w),
+			Path:     index.Path(),
+			Entries:  index.Len(),
+		})
+	},
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+	workshopSource = resolution.Source
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", indexCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", indexCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", indexCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", indexCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", indexCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", indexCmd)
+
+	indexCmd.AddCommand(rebuildCmd)
+
+	return indexCmd
+}
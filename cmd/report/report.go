@@ -0,0 +1,205 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	progressreport "github.com/andrerfcsantos/kody/lib/report"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop *workshop.Workshop
+	allWorkshops    bool
+	outputDir       string
+	format          string
+	reportFile      string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports about your progress on the workshops",
+	Long: `Generate a progress report for the current workshop, or for all workshops with --all.
+
+The report lists the sections and exercises of each workshop with their completion status, when they were saved, the time spent on them, the result of their last test run and links to the saved solutions. It is written in Markdown, to use as the README of your solutions repository, or as a standalone HTML page.
+
+Links to the saved solutions are relative to the folder of --file, or to the output directory when the report is printed.`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkAndSetupConfigs(cmd); err != nil {
+			return output.WithCode(output.CodeWorkshopNotFound, err)
+		}
+		// Without it, saved solutions would be searched in the current directory and none would be found
+		if outputDir == "" {
+			return output.WithCode(output.CodeInvalidConfig, errors.New("flag error: please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration"))
+		}
+		// With json and yaml the report data is printed, there is no rendered report to write to --file
+		if out, _ := output.FromConfig(cfg); !out.IsText() && reportFile != "" {
+			return output.WithCode(output.CodeInvalidConfig, errors.New("flag error: --file can only be used with the text output, with --output json or yaml the report is printed"))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		reportFormat, err := progressreport.ParseFormat(format)
+		if err != nil {
+			return output.WithCode(output.CodeInvalidConfig, err)
+		}
+
+		workshops := []*workshop.Workshop{currentWorkshop}
+		if allWorkshops {
+			workshops, err = findWorkshops(out)
+			if err != nil {
+				return output.WithCode(output.CodeWorkshopNotFound, err)
+			}
+		}
+
+		report, err := buildReport(workshops, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !out.IsText() {
+			return out.Document(report)
+		}
+
+		var buf bytes.Buffer
+		if err := progressreport.Render(&buf, reportFormat, report); err != nil {
+			return err
+		}
+
+		if reportFile == "" {
+			out.Print(buf.String())
+			return nil
+		}
+
+		if err := os.WriteFile(reportFile, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		out.Printf("Report written to '%s'\n", reportFile)
+
+		return nil
+	},
+}
+
+func findWorkshops(out *output.Printer) ([]*workshop.Workshop, error) {
+	searchOptions := workshop.SearchOptionsFromConfig(cfg)
+	if len(searchOptions.Roots) == 0 {
+		return nil, errors.New("please provide the directories where the workshops are located using the --workshops or --roots flags, or the workshops.dir or workshops.roots configurations")
+	}
+
+	paths, err := workshop.FindWorkshops(searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("finding workshops: %w", err)
+	}
+
+	var workshops []*workshop.Workshop
+	for _, path := range paths {
+		w, err := workshop.WorkshopFromPath(path)
+		if err != nil {
+			out.Printf("Skipping '%s': %v\n", path, err)
+			continue
+		}
+		workshops = append(workshops, w)
+	}
+
+	return workshops, nil
+}
+
+func buildReport(workshops []*workshop.Workshop, now time.Time) (*progressreport.Report, error) {
+	db, err := progress.Open(config.DefaultProgressDBPath(cfg))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// Time is estimated across all workshops, so switching between them doesn't count time twice
+	events, err := db.Events(progress.Query{})
+	if err != nil {
+		return nil, err
+	}
+	timeSpent := progress.NewTimeReport(progress.ActiveIntervals(events, progress.DefaultIdleThreshold), 0, now)
+
+	testRuns := make(map[string]map[string]progress.Event)
+	for _, w := range workshops {
+		testRuns[w.Slug()], err = db.Latest(w.Slug(), progress.Tested)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	linkBase := outputDir
+	if reportFile != "" {
+		linkBase = filepath.Dir(reportFile)
+	}
+	linkBase, err = filepath.Abs(linkBase)
+	if err != nil {
+		return nil, fmt.Errorf("getting absolute path of '%s': %w", linkBase, err)
+	}
+
+	return progressreport.Build(workshops, progressreport.Options{
+		OutputDir: outputDir,
+		LinkBase:  linkBase,
+		TimeSpent: timeSpent,
+		TestRuns:  testRuns,
+		Now:       now,
+	})
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+
+	if allWorkshops {
+		return nil
+	}
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", reportCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", reportCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", reportCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", reportCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", reportCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", reportCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", reportCmd)
+
+	reportCmd.PersistentFlags().BoolVarP(&allWorkshops, "all", "a", false, "Report on all the workshops instead of only the current one")
+
+	reportCmd.Flags().StringVar(&format, "format", string(progressreport.Markdown), "Format of the report: markdown or html")
+	reportCmd.Flags().StringVarP(&reportFile, "file", "f", "", "Write the report to this file instead of printing it")
+
+	timeCmd.Flags().DurationVar(&idleThreshold, "idle", progress.DefaultIdleThreshold, "Gaps between activities longer than this are considered breaks")
+	timeCmd.Flags().IntVar(&weeks, "weeks", 4, "Number of weeks to show the totals of")
+	reportCmd.AddCommand(timeCmd)
+
+	return reportCmd
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	progressreport "github.com/andrerfcsantos/kody/lib/report"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const weekFormat = "2006-01-02"
+
+var (
+	idleThreshold time.Duration
+	weeks         int
+)
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Show the time spent on each exercise",
+	Long: `Show an estimate of the active time spent on the current workshop, or on all workshops with --all, broken down by section and exercise, with the totals of the last weeks.
+
+The time is estimated from the activity kody records: the exercises it detects in the playground, edits to the playground, saves, restores and test runs. Gaps between two activities longer than --idle are considered breaks and left out. Running kody watch gives the best estimates, since it records every change to the playground.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if weeks < 1 {
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: --weeks must be at least 1, got %d", weeks))
+		}
+		if idleThreshold <= 0 {
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: --idle must be positive, got %s", idleThreshold))
+		}
+		return output.WithCode(output.CodeWorkshopNotFound, checkAndSetupConfigs(cmd))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		report, err := timeReport(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !allWorkshops {
+			// Time is estimated across all workshops, so switching between them doesn't count time twice
+			filtered := &progress.TimeReport{Weeks: report.Weeks}
+			if w := report.Workshop(currentWorkshop.Slug()); w != nil {
+				filtered.Total = w.Total
+				filtered.Weeks = w.Weeks
+				filtered.Workshops = []*progress.WorkshopTime{w}
+			} else {
+				for i := range filtered.Weeks {
+					filtered.Weeks[i].Total = 0
+				}
+			}
+			report = filtered
+		}
+
+		if len(report.Workshops) == 0 {
+			out.Println("No time recorded yet. Kody records activity every time it runs, and continuously with kody watch.")
+		}
+
+		for _, w := range report.Workshops {
+			out.Printf("%s: %s\n", w.Workshop, progressreport.FormatDuration(w.Total))
+			for _, section := range w.Sections {
+				out.Printf("\n  %-50s %10s\n", section.Name, progressreport.FormatDuration(section.Total))
+				for _, exercise := range section.Exercises {
+					out.Printf("    %-48s %10s\n", exercise.BreadCrumbs, progressreport.FormatDuration(exercise.Total))
+				}
+			}
+			out.Println()
+		}
+
+		if len(report.Workshops) > 1 {
+			out.Printf("Total: %s\n\n", progressreport.FormatDuration(report.Total))
+		}
+
+		out.Println("Week over week:")
+		for i, week := range report.Weeks {
+			change := ""
+			if i > 0 {
+				change = fmt.Sprintf(" (%s vs previous week)", formatChange(week.Total-report.Weeks[i-1].Total))
+			}
+			out.Printf("  Week of %s  %10s%s\n", week.Start.Format(weekFormat), progressreport.FormatDuration(week.Total), change)
+		}
+
+		return out.Document(report)
+	},
+}
+
+func timeReport(now time.Time) (*progress.TimeReport, error) {
+	db, err := progress.Open(config.DefaultProgressDBPath(cfg))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	events, err := db.Events(progress.Query{})
+	if err != nil {
+		return nil, err
+	}
+
+	return progress.NewTimeReport(progress.ActiveIntervals(events, idleThreshold), weeks, now), nil
+}
+
+func formatChange(d time.Duration) string {
+	if d < 0 {
+		return "-" + progressreport.FormatDuration(-d)
+	}
+	return "+" + progressreport.FormatDuration(d)
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/andrerfcsantos/kody/lib/config"
-	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/solutions"
 	"github.com/andrerfcsantos/kody/lib/workshop"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -19,43 +19,32 @@ var (
 )
 
 var (
-	workshopPath    string
-	workshopsDir    string
 	currentWorkshop *workshop.Workshop
+	workshopSource  workshop.ResolutionSource
 	outputDir       string
 	sectionNo       int
 	exerciseNo      int
+	merge           bool
 )
 
 func checkAndSetupConfigs(cmd *cobra.Command) error {
-	workshopPath = cfg.GetString("workshop.path")
-	workshopsDir = cfg.GetString("workshops.dir")
 	outputDir = cfg.GetString("save.output.directory")
 
-	// Check if flags were passed directly
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
 	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
-		workshopPath = workshopPathFlag.Value.String()
-	}
-	if workshopsDirFlag := cmd.Flags().Lookup("workshops-dir"); workshopsDirFlag != nil && workshopsDirFlag.Changed {
-		workshopsDir = workshopsDirFlag.Value.String()
-	}
-
-	// If workshopPath is not provided but workshopsDir is, auto-detect the current workshop
-	if workshopPath == "" && workshopsDir != "" {
-		var err error
-		currentWorkshop, err = workshop.DetectCurrentWorkshop(workshopsDir)
-		if err != nil {
-			return fmt.Errorf("auto-detecting workshop from workshopsDir '%s': %w", workshopsDir, err)
-		}
-		workshopPath = currentWorkshop.Path
+		flagPath = workshopPathFlag.Value.String()
 	}
 
-	if workshopPath == "" {
-		return errors.New("please provide a path to the workshop folder using the --workshop flag or the workshop.path configuration, or use --workshops-dir to auto-detect")
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
 	}
+	currentWorkshop = resolution.Workshop
+	workshopSource = resolution.Source
 
 	if outputDir == "" {
-		return errors.New("please provide a path to the output directory using the --output flag or the save.output.directory configuration")
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
 	}
 
 	return nil
@@ -65,10 +54,12 @@ var restoreCmd = &cobra.Command{
 	Use:    "restore [exercise]",
 	Hidden: true,
 	Short:  "Restore an exercise to the playground",
-	Long:   `Restore an exercise to the playground. If no exercise is specified, automatically detects the current exercise from the playground.`,
+	Long: `Restore an exercise to the playground. If no exercise is specified, automatically detects the current exercise from the playground.
+
+By default the files of the saved solution overwrite the ones in the playground. With --merge, the changes you made in the playground since the exercise was set are kept: each file is merged with a three-way merge, using the problem files of the exercise as the common base. Lines changed both in the playground and in the saved solution get conflict markers, and the files with conflicts are listed.`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if err := checkAndSetupConfigs(cmd); err != nil {
-			return fmt.Errorf("flag error: %w", err)
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: %w", err))
 		}
 		return nil
 	},
@@ -102,65 +93,154 @@ var restoreCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var w *workshop.Workshop
-		var err error
+		out, _ := output.FromConfig(cfg)
 
-		// Use the already loaded workshop if available, otherwise load it from path
-		if currentWorkshop != nil {
-			w = currentWorkshop
-		} else {
-			w, err = workshop.WorkshopFromPath(workshopPath)
-			if err != nil {
-				return fmt.Errorf("getting workshop from path '%s': %w", workshopPath, err)
-			}
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		doc := restoreDocument{
+			Workshop:      output.NewWorkshopInfo(w),
+			SectionNumber: sectionNo,
+			Number:        exerciseNo,
+			Destination:   w.PlaygroundPath(),
+			Actions:       []string{},
 		}
 
 		// If no exercise was specified, auto-detect from the playground
 		if len(args) == 0 {
 			playgroundExercise, err := w.PlaygroundExercise()
 			if err != nil {
-				return fmt.Errorf("auto-detecting exercise from playground: %w", err)
+				return output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("auto-detecting exercise from playground: %w", err))
 			}
+			doc.Exercise = output.NewExerciseInfo(playgroundExercise)
+			doc.SectionNumber = playgroundExercise.Section.Number
+			doc.Number = playgroundExercise.Number
 
 			// Use the section and exercise numbers from the detected exercise
 			sectionNo = playgroundExercise.Section.Number
 			exerciseNo = playgroundExercise.Number
 
-			fmt.Printf("Auto-detected exercise: %s > %s\n", playgroundExercise.BreadCrumbsWithWorkshop(w.Slug()), playgroundExercise.Descriptor())
+			out.Printf("Auto-detected exercise: %s > %s\n", playgroundExercise.BreadCrumbsWithWorkshop(w.Slug()), playgroundExercise.Descriptor())
 		}
 
-		sectionGlob := fmt.Sprintf("%02d.*", sectionNo)
-		exerciseGlob := fmt.Sprintf("%02d.*", exerciseNo)
-		searchGlob := filepath.Join(outputDir, w.Slug(), sectionGlob, exerciseGlob)
+		restorePath, err := solutions.FindSaved(outputDir, w, sectionNo, exerciseNo)
+		if errors.Is(err, solutions.ErrNotSaved) {
+			return output.WithCode(output.CodeSavedExerciseMissing, err)
+		}
+		if err != nil {
+			return err
+		}
 
-		matches, err := filepath.Glob(searchGlob)
+		doc.Source = restorePath
+
+		outline, err := w.Outline()
 		if err != nil {
-			return fmt.Errorf("globbing files: %w", err)
+			return fmt.Errorf("loading workshop: %w", err)
 		}
+		restored := outline.Exercise(strconv.Itoa(sectionNo), strconv.Itoa(exerciseNo))
+
+		if merge {
+			if restored == nil || restored.ProblemPath == "" {
+				return output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("exercise %02d.%02d has no problem directory in the workshop to merge from", sectionNo, exerciseNo))
+			}
 
-		if len(matches) == 0 {
-			return fmt.Errorf("no candidate directory in the output directory found for the exercise was found (search glob: %s)", searchGlob)
+			doc.Merge, err = solutions.MergeRestore(w, restorePath, restored.ProblemPath)
+			if err != nil {
+				return output.WithCode(output.CodeCopyFailed, err)
+			}
+			doc.Actions = append(doc.Actions, "merged")
+		} else {
+			err = solutions.Restore(w, restorePath)
+			if err != nil {
+				return output.WithCode(output.CodeCopyFailed, err)
+			}
+			doc.Actions = append(doc.Actions, "restored")
 		}
 
-		if len(matches) != 1 {
-			return fmt.Errorf("more than one candidate directory in the output directory found for the exercise was found")
+		if restored != nil {
+			event := progress.NewEvent(progress.Restored, w, restored.Exercise)
+			event.Path = restorePath
+			if err := progress.Record(config.DefaultProgressDBPath(cfg), event); err != nil {
+				out.Printf("Warning: could not record progress: %v\n", err)
+			}
 		}
 
-		restorePath := matches[0]
-		err = directory.CopyFS(w.PlaygroundPath(), os.DirFS(restorePath))
-		if err != nil {
-			return fmt.Errorf("restoring files: %w", err)
+		if doc.Merge != nil {
+			printMerge(out, doc.Merge)
+			if len(doc.Merge.Conflicts) == 0 {
+				return out.Document(doc)
+			}
+
+			// Conflicts are not a usage error
+			cmd.SilenceUsage = true
+			conflictsErr := output.WithCode(output.CodeMergeConflicts, fmt.Errorf("%d files have conflicts, resolve them in the playground", len(doc.Merge.Conflicts)))
+			if len(doc.Merge.Conflicts) == 1 {
+				conflictsErr = output.WithCode(output.CodeMergeConflicts, errors.New("1 file has conflicts, resolve them in the playground"))
+			}
+			doc.Error = output.NewErrorInfo(conflictsErr)
+			if err := out.Document(doc); err != nil {
+				return err
+			}
+			return output.Reported(conflictsErr)
 		}
-		return nil
+
+		out.Printf("Restored exercise from '%s' > '%s'\n", restorePath, w.PlaygroundPath())
+		return out.Document(doc)
 	},
 }
 
+type restoreDocument struct {
+	Workshop      *output.WorkshopInfo   `json:"workshop" yaml:"workshop"`
+	Exercise      *output.ExerciseInfo   `json:"exercise,omitempty" yaml:"exercise,omitempty"`
+	SectionNumber int                    `json:"sectionNumber" yaml:"sectionNumber"`
+	Number        int                    `json:"number" yaml:"number"`
+	Source        string                 `json:"source" yaml:"source"`
+	Destination   string                 `json:"destination" yaml:"destination"`
+	Actions       []string               `json:"actions" yaml:"actions"`
+	Merge         *solutions.MergeResult `json:"merge,omitempty" yaml:"merge,omitempty"`
+	Error         *output.ErrorInfo      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func printMerge(out *output.Printer, merge *solutions.MergeResult) {
+	for _, path := range merge.Updated {
+		out.Printf("  updated    %s\n", path)
+	}
+	for _, path := range merge.Deleted {
+		out.Printf("  deleted    %s\n", path)
+	}
+	for _, path := range merge.Merged {
+		out.Printf("  merged     %s\n", path)
+	}
+	for _, conflict := range merge.Conflicts {
+		out.Printf("  CONFLICT   %s: %s\n", conflict.Path, conflict.Reason)
+	}
+
+	changed := len(merge.Updated) + len(merge.Deleted) + len(merge.Merged) + len(merge.Conflicts)
+	if changed == 0 {
+		out.Println("The playground already has all the changes of the saved solution.")
+	} else if len(merge.Conflicts) == 0 {
+		out.Println("Merged the saved solution into the playground, keeping your changes.")
+	} else {
+		out.Println("Merged the saved solution into the playground. Resolve the conflicts by editing the files between the <<<<<<< and >>>>>>> markers.")
+	}
+}
+
 func GetCmd(configuration *config.Config) *cobra.Command {
 	cfg = configuration
 
 	cfg.BindFlagConfigToCommand("workshop.dir", restoreCmd)
 	cfg.BindFlagConfigToCommand("workshops.dir", restoreCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", restoreCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", restoreCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", restoreCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", restoreCmd)
 	cfg.BindFlagConfigToCommand("save.output.directory", restoreCmd)
 
+	restoreCmd.Flags().BoolVar(&merge, "merge", false, "Merge the saved solution with the changes in the playground instead of overwriting them, marking the lines changed in both")
+
 	return restoreCmd
 }
@@ -1,14 +1,25 @@
 package cmd
 
 import (
-	"fmt"
 	configCmd "github.com/andrerfcsantos/kody/cmd/config"
+	"github.com/andrerfcsantos/kody/cmd/diff"
+	"github.com/andrerfcsantos/kody/cmd/exercises"
+	"github.com/andrerfcsantos/kody/cmd/history"
+	"github.com/andrerfcsantos/kody/cmd/index"
+	"github.com/andrerfcsantos/kody/cmd/report"
 	"github.com/andrerfcsantos/kody/cmd/restore"
 	"github.com/andrerfcsantos/kody/cmd/save"
+	"github.com/andrerfcsantos/kody/cmd/site"
 	"github.com/andrerfcsantos/kody/cmd/status"
+	"github.com/andrerfcsantos/kody/cmd/sync"
 	"github.com/andrerfcsantos/kody/cmd/test"
+	"github.com/andrerfcsantos/kody/cmd/verify"
 	"github.com/andrerfcsantos/kody/cmd/version"
+	"github.com/andrerfcsantos/kody/cmd/watch"
+	"github.com/andrerfcsantos/kody/cmd/workshops"
 	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/workshop"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -35,6 +46,34 @@ func init() {
 		Description:   "Path to the workshops directory, where all the workshops sub-directories are located. If this is provided, the current workshop will be automatically calculated to be the one with the most recent playground modification time. Use the --workshop flag if don't want to use this automatic workshop detection. [config key: workshops.dir]",
 	})
 
+	config.AddFlagConfig(cfg, config.FlagConfig[[]string]{
+		Key:         "workshops.roots",
+		FlagName:    "roots",
+		Default:     nil,
+		Description: "Additional directories where workshops are located, in addition to the --workshops directory. The current workshop is auto-detected as the one with the most recently modified playground across all of them. [config key: workshops.roots]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[int]{
+		Key:         "workshops.depth",
+		FlagName:    "depth",
+		Default:     1,
+		Description: "How many directory levels below each workshops directory are searched for workshops. Use 2 or more if your workshops are grouped in sub-folders. [config key: workshops.depth]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[[]string]{
+		Key:         "workshops.include",
+		FlagName:    "include",
+		Default:     nil,
+		Description: "Only consider workshops whose folder name or path relative to the workshops directory matches one of these glob patterns. [config key: workshops.include]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[[]string]{
+		Key:         "workshops.exclude",
+		FlagName:    "exclude",
+		Default:     nil,
+		Description: "Ignore workshops and directories whose folder name or path relative to the workshops directory matches one of these glob patterns. [config key: workshops.exclude]",
+	})
+
 	config.AddFlagConfig(cfg, config.FlagConfig[string]{
 		Key:           "save.output.directory",
 		FlagName:      "output-dir",
@@ -55,15 +94,62 @@ func init() {
 		Key:           "save.commit.message",
 		FlagName:      "commitMessage",
 		FlagShortHand: "m",
-		Default:       "[{{ .Workshop.Slug }}] Add exercise {{ .Exercise.BreadCrumbs }}",
+		Default:       "[{{ .Workshop.Slug }}] Add exercise {{ .Exercise.BreadCrumbs }}{{ if .Tests }}\n\n{{ .Tests.Summary }}{{ end }}",
 		Description:   "Commit message to use, in case the --commit flag is set or the save.shouldCommit configuration is set to true. The template is rendered using Go's text/template package.",
 	})
 
+	config.AddFlagConfig(cfg, config.FlagConfig[bool]{
+		Key:         "save.requireTests",
+		FlagName:    "verify",
+		Default:     false,
+		Description: "Run the tests of the exercise in the playground before saving it, and only save and commit it if they pass. [config key: save.requireTests]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[string]{
+		Key:         "save.index",
+		FlagName:    "index",
+		Default:     "README.md",
+		Description: "Name of the index of the saved solutions that kody keeps up to date in the output directory and in the folder of each workshop. Set it to an empty string to not keep indexes. [config key: save.index]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[string]{
+		Key:         "watch.debounce",
+		FlagName:    "debounce",
+		Default:     "3s",
+		Description: "How long the playground must go without changes before kody watch saves it, e.g. 3s or 1m. [config key: watch.debounce]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[bool]{
+		Key:         "watch.sync",
+		FlagName:    "sync",
+		Default:     false,
+		Description: "While watching, keep the last known state of the playground and save the previous exercise when the playground is switched to another one. [config key: watch.sync]",
+	})
+
+	config.AddFlagConfig(cfg, config.FlagConfig[string]{
+		Key:         "output.format",
+		FlagName:    "output",
+		Default:     string(output.Text),
+		Description: "Output format of the commands: text, json or yaml. [config key: output.format]",
+	})
+
+	cfg.BindFlagConfigToCommand("output.format", rootCmd)
+
 	rootCmd.AddCommand(save.GetCmd(cfg))
 	rootCmd.AddCommand(restore.GetCmd(cfg))
 	rootCmd.AddCommand(status.GetCmd(cfg))
 	rootCmd.AddCommand(configCmd.GetCmd(cfg))
 	rootCmd.AddCommand(test.GetCmd(cfg))
+	rootCmd.AddCommand(index.GetCmd(cfg))
+	rootCmd.AddCommand(workshops.GetCmd(cfg))
+	rootCmd.AddCommand(exercises.GetCmd(cfg))
+	rootCmd.AddCommand(watch.GetCmd(cfg))
+	rootCmd.AddCommand(sync.GetCmd(cfg))
+	rootCmd.AddCommand(verify.GetCmd(cfg))
+	rootCmd.AddCommand(history.GetCmd(cfg))
+	rootCmd.AddCommand(report.GetCmd(cfg))
+	rootCmd.AddCommand(site.GetCmd(cfg))
+	rootCmd.AddCommand(diff.GetCmd(cfg))
 	rootCmd.AddCommand(version.GetCmd(cfg))
 }
 
@@ -72,14 +158,33 @@ var rootCmd = &cobra.Command{
 	Short: "CLI tool to help manage Epic React Dev workshops and exercises.",
 	Long:  `Management of Epic React Dev workshops and exercises and other automation tasks.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return cfg.Read()
+		err := cfg.Read()
+		if err != nil {
+			return output.WithCode(output.CodeInvalidConfig, err)
+		}
+		cfg.BindCommandFlags(cmd)
+		workshop.SetSlugOverrides(cfg.GetFlatStringMap("workshop.slugs"))
+
+		out, err := output.FromConfig(cfg)
+		if err != nil {
+			return output.WithCode(output.CodeInvalidConfig, err)
+		}
+
+		// Keep the standard output parseable in the structured formats
+		if !out.IsText() {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+
+		return nil
 	},
 }
 
 func Execute(buildInfo config.BuildInfo) {
 	cfg.SetBuildInfo(buildInfo)
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		out, _ := output.FromConfig(cfg)
+		out.Error(err, os.Stderr)
 		os.Exit(1)
 	}
 }
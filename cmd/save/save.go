@@ -3,12 +3,14 @@ package save
 import (
 	"errors"
 	"fmt"
-	"github.com/andrerfcsantos/kody/lib/cmder"
 	"github.com/andrerfcsantos/kody/lib/config"
-	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/prompt"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/testrun"
 	"github.com/andrerfcsantos/kody/lib/workshop"
 	"os"
-	"strings"
 	"text/template"
 
 	"github.com/spf13/cobra"
@@ -19,48 +21,39 @@ var (
 )
 
 var (
-	workshopPath          string
-	workshopsDir          string
 	currentWorkshop       *workshop.Workshop
+	workshopSource        workshop.ResolutionSource
 	outputDir             string
 	shouldCommit          bool
+	requireTests          bool
+	indexFile             string
 	commitMessageTemplate *template.Template
 )
 
 func checkAndSetupConfigs(cmd *cobra.Command) error {
-	workshopPath = cfg.GetString("workshop.path")
-	workshopsDir = cfg.GetString("workshops.dir")
 	outputDir = cfg.GetString("save.output.directory")
 	shouldCommit = cfg.GetBool("save.shouldCommit")
+	requireTests = cfg.GetBool("save.requireTests")
+	indexFile = cfg.GetString("save.index")
 	commitMessageTemplateString := cfg.GetString("save.commit.message")
 
-	// Check if flags were passed directly
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
 	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
-		workshopPath = workshopPathFlag.Value.String()
-	}
-	if workshopsDirFlag := cmd.Flags().Lookup("workshops-dir"); workshopsDirFlag != nil && workshopsDirFlag.Changed {
-		workshopsDir = workshopsDirFlag.Value.String()
-	}
-
-	// If workshopPath is not provided but workshopsDir is, auto-detect the current workshop
-	if workshopPath == "" && workshopsDir != "" {
-		var err error
-		currentWorkshop, err = workshop.DetectCurrentWorkshop(workshopsDir)
-		if err != nil {
-			return fmt.Errorf("auto-detecting workshop from workshopsDir '%s': %w", workshopsDir, err)
-		}
-		workshopPath = currentWorkshop.Path
+		flagPath = workshopPathFlag.Value.String()
 	}
 
-	if workshopPath == "" {
-		return errors.New("please provide a path to the workshop folder using the --workshop flag or the workshop.path configuration, or use --workshops-dir to auto-detect")
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
 	}
+	currentWorkshop = resolution.Workshop
+	workshopSource = resolution.Source
 
 	if outputDir == "" {
-		return errors.New("please provide a path to the output directory using the --output flag or the save.output.directory configuration")
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
 	}
 
-	var err error
 	commitMessageTemplate, err = template.New("commitMessage").Parse(commitMessageTemplateString)
 	if err != nil {
 		return fmt.Errorf("parsing commit message template: %w", err)
@@ -75,98 +68,149 @@ var saveCmd = &cobra.Command{
 	Long:  `This command allows to save the current contents of a playground to a more permanent location.`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		if err := checkAndSetupConfigs(cmd); err != nil {
-			return fmt.Errorf("flag error: %w", err)
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: %w", err))
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var w *workshop.Workshop
-		var err error
-
-		// Use the already loaded workshop if available, otherwise load it from path
-		if currentWorkshop != nil {
-			w = currentWorkshop
-		} else {
-			w, err = workshop.WorkshopFromPath(workshopPath)
-			if err != nil {
-				return fmt.Errorf("getting workshop from path '%s': %w", workshopPath, err)
-			}
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		for _, warning := range w.Warnings() {
+			out.Printf("Warning: %s\n", warning)
 		}
 
 		exercise, err := w.PlaygroundExercise()
 		if err != nil {
-			return fmt.Errorf("getting playground exercise: %w", err)
+			return output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("getting playground exercise: %w", err))
 		}
 
-		fmt.Printf("Looks like you are doing exercise %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
+		out.Printf("Looks like you are doing exercise %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
 
 		exerciseDir := workshop.DefaultExerciseDir(outputDir, w, exercise)
-		err = workshop.CopyExercise(w.PlaygroundPath(), exerciseDir)
-		if err != nil {
-			return fmt.Errorf("error copying exercise %s > %s: %w", w.PlaygroundPath(), outputDir, err)
+		doc := saveDocument{
+			Workshop:    output.NewWorkshopInfo(w),
+			Exercise:    output.NewExerciseInfo(exercise),
+			Source:      w.PlaygroundPath(),
+			Destination: exerciseDir,
+			Actions:     []string{},
 		}
 
-		if shouldCommit {
-			// commitMessage := fmt.Sprintf("[%s] Add exercise %s", w.Slug(), exercise.Descriptor())
-			commitMessageWriter := &strings.Builder{}
-			err = commitMessageTemplate.Execute(commitMessageWriter, TemplateData{Workshop: w, Exercise: exercise})
-			if err != nil {
-				return fmt.Errorf("rendering commit message template: %w", err)
+		if exercise.IsSolution() {
+			out.Println("Warning: the playground is set to the official solution of this exercise, not to the problem.")
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+				if !out.IsText() {
+					return output.WithCode(output.CodeConfirmationRequired, errors.New("the playground is set to the official solution of this exercise, pass --yes to save it anyway"))
+				}
+
+				confirmed, err := prompt.Confirm("Save the official solution as your own solution anyway?")
+				if err != nil {
+					return fmt.Errorf("confirming save of official solution: %w", err)
+				}
+				if !confirmed {
+					out.Println("Nothing was saved.")
+					return nil
+				}
 			}
+		}
 
-			err = HandleCommit(outputDir, exerciseDir, commitMessageWriter.String())
-			if err != nil {
-				return fmt.Errorf("committing exercise '%s': %w", exerciseDir, err)
+		saveOpts := solutions.SaveOptions{
+			OutputDir:     outputDir,
+			Commit:        shouldCommit,
+			CommitMessage: commitMessageTemplate,
+			RequireTests:  requireTests,
+			IndexFile:     indexFile,
+		}
+		if requireTests {
+			// Structured outputs are written to stdout, so the output of the tests goes to stderr
+			saveOpts.TestOutput = os.Stdout
+			if !out.IsText() {
+				saveOpts.TestOutput = os.Stderr
 			}
-
+			out.Println("Running the tests before saving...")
 		}
 
-		fmt.Printf("Copied exercise from playground '%s' > '%s'\n", w.PlaygroundPath(), exerciseDir)
-		return nil
-	},
-}
+		result, err := solutions.Save(w, exercise, saveOpts)
+		events := progress.DetectionEvents(w, exercise)
+		if result.Tests != nil {
+			doc.Tests = result.Tests
+			out.Printf("\n%s\n", result.Tests.Summary())
+			events = append(events, progress.NewTestEvent(w, exercise, result.Tests))
+		}
+		if result.Copied {
+			saved := progress.NewEvent(progress.Saved, w, exercise)
+			saved.Path = result.Destination
+			events = append(events, saved)
+		}
+		if recordErr := progress.Record(config.DefaultProgressDBPath(cfg), events...); recordErr != nil {
+			out.Printf("Warning: could not record progress: %v\n", recordErr)
+		}
 
-func HandleCommit(repoPath string, exercisePath string, message string) error {
-	if !directory.IsGitRepo(repoPath) {
-		return fmt.Errorf("output directory '%s' is not a git repository", repoPath)
-	}
+		for _, warning := range result.Warnings {
+			out.Printf("Warning: %s\n", warning)
+		}
+		doc.Warnings = result.Warnings
+		doc.Indexes = result.Indexes
 
-	dir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("getting current working directory: %w", err)
-	}
+		out.Print(result.GitOutput)
+		if err != nil {
+			if errors.Is(err, solutions.ErrTestsFailed) {
+				// Failing tests are not a usage error
+				cmd.SilenceUsage = true
+				out.Println("Nothing was saved.")
+			}
+			return output.WithCode(saveErrorCode(err), err)
+		}
 
-	err = os.Chdir(repoPath)
-	if err != nil {
-		return fmt.Errorf("changing directory to '%s': %w", repoPath, err)
-	}
+		doc.Actions = append(doc.Actions, "copied")
+		if result.Committed {
+			doc.Actions = append(doc.Actions, "committed")
+			doc.CommitMessage = result.CommitMessage
+		}
 
-	output, err := cmder.ExecuteCommand("git", "add", "-A", exercisePath)
-	if err != nil {
-		fmt.Print(output)
-		return fmt.Errorf("adding exercise to git repository: %w", err)
-	}
+		out.Printf("Copied exercise from playground '%s' > '%s'\n", w.PlaygroundPath(), exerciseDir)
+		return out.Document(doc)
+	},
+}
 
-	fmt.Println(output)
+type saveDocument struct {
+	Workshop      *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Exercise      *output.ExerciseInfo `json:"exercise" yaml:"exercise"`
+	Source        string               `json:"source" yaml:"source"`
+	Destination   string               `json:"destination" yaml:"destination"`
+	Actions       []string             `json:"actions" yaml:"actions"`
+	CommitMessage string               `json:"commitMessage,omitempty" yaml:"commitMessage,omitempty"`
+	Tests         *testrun.Result      `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Indexes       []string             `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+	Warnings      []string             `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
 
-	output, err = cmder.ExecuteCommand("git", "commit", "-m", message)
-	if err != nil {
-		return fmt.Errorf("committing exercise to git repository: %w", err)
+func saveErrorCode(err error) output.Code {
+	var saveErr *solutions.SaveError
+	if !errors.As(err, &saveErr) {
+		return output.CodeUnknown
 	}
 
-	fmt.Println(output)
-
-	err = os.Chdir(dir)
-	if err != nil {
-		return fmt.Errorf("changing directory back to '%s': %w", dir, err)
+	switch saveErr.Step {
+	case solutions.TestStep:
+		if errors.Is(err, testrun.ErrNoTests) {
+			return output.CodeTestsNotFound
+		}
+		return output.CodeTestsFailed
+	case solutions.CopyStep, solutions.IndexStep:
+		return output.CodeCopyFailed
+	case solutions.TemplateStep:
+		return output.CodeInvalidConfig
+	case solutions.CommitStep:
+		return output.CodeCommitFailed
 	}
-
-	return nil
-}
-
-type TemplateData struct {
-	Workshop *workshop.Workshop
-	Exercise *workshop.Exercise
+	return output.CodeUnknown
 }
 
 func GetCmd(configuration *config.Config) *cobra.Command {
@@ -174,9 +218,17 @@ func GetCmd(configuration *config.Config) *cobra.Command {
 
 	cfg.BindFlagConfigToCommand("workshop.dir", saveCmd)
 	cfg.BindFlagConfigToCommand("workshops.dir", saveCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", saveCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", saveCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", saveCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", saveCmd)
 	cfg.BindFlagConfigToCommand("save.output.directory", saveCmd)
 	cfg.BindFlagConfigToCommand("save.shouldCommit", saveCmd)
 	cfg.BindFlagConfigToCommand("save.commit.message", saveCmd)
+	cfg.BindFlagConfigToCommand("save.requireTests", saveCmd)
+	cfg.BindFlagConfigToCommand("save.index", saveCmd)
+
+	saveCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before saving a playground that is set to an official solution")
 
 	return saveCmd
 }
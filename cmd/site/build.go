@@ -0,0 +1,84 @@
+package site
+
+import (
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/output"
+	staticsite "github.com/andrerfcsantos/kody/lib/site"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dest string
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build a static website with your saved solutions",
+	Long: `Build a static website to browse the saved solutions in the output directory, with a sidebar to navigate by workshop, section and exercise.
+
+The page of each exercise shows its instructions, the files of your solution with syntax highlighting, and how your solution differs from the official one. The instructions and the official solutions are taken from the workshops kody finds in the workshops directories.
+
+By default the site is built in the docs folder of the output directory, so it can be published with GitHub Pages from the solutions repository. Its previous contents are replaced on every build. The pages load the libraries to render Markdown and highlight code from a CDN.`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.WithCode(output.CodeInvalidConfig, checkAndSetupConfigs(cmd))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		if dest == "" {
+			dest = filepath.Join(outputDir, "docs")
+		}
+
+		workshops, err := localWorkshops()
+		if err != nil {
+			return output.WithCode(output.CodeWorkshopNotFound, err)
+		}
+
+		result, err := staticsite.Build(staticsite.Options{
+			OutputDir: outputDir,
+			Dest:      dest,
+			Workshops: workshops,
+			Now:       time.Now(),
+		})
+		for _, warning := range result.Warnings {
+			out.Printf("Warning: %s\n", warning)
+		}
+		if err != nil {
+			return fmt.Errorf("building site: %w", err)
+		}
+
+		out.Printf("Built %d pages for %d exercises of %d workshops in '%s'\n", result.Pages, result.Exercises, result.Workshops, result.Dest)
+		out.Printf("Open '%s' in your browser to see it\n", filepath.Join(result.Dest, "index.html"))
+
+		return out.Document(result)
+	},
+}
+
+// localWorkshops loads the workshops in the workshops directories, if there are any.
+func localWorkshops() ([]*workshop.Workshop, error) {
+	searchOptions := workshop.SearchOptionsFromConfig(cfg)
+	if len(searchOptions.Roots) == 0 {
+		return nil, nil
+	}
+
+	paths, err := workshop.FindWorkshops(searchOptions)
+	if err != nil {
+		return nil, fmt.Errorf("finding workshops: %w", err)
+	}
+
+	var workshops []*workshop.Workshop
+	for _, path := range paths {
+		w, err := workshop.WorkshopFromPath(path)
+		if err != nil {
+			continue // Their exercises are shown without instructions nor official solutions
+		}
+		workshops = append(workshops, w)
+	}
+
+	return workshops, nil
+}
@@ -0,0 +1,46 @@
+package site
+
+import (
+	"errors"
+	"github.com/andrerfcsantos/kody/lib/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	outputDir string
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Static website to browse your saved solutions",
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+	if outputDir == "" {
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
+	}
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshops.dir", siteCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", siteCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", siteCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", siteCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", siteCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", siteCmd)
+
+	buildCmd.Flags().StringVar(&dest, "dest", "", "Directory to build the site in (default: the docs folder of the output directory)")
+	siteCmd.AddCommand(buildCmd)
+
+	return siteCmd
+}
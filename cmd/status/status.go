@@ -1,9 +1,14 @@
 package status
 
 import (
+	"errors"
 	"fmt"
 	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/testrun"
 	"github.com/andrerfcsantos/kody/lib/workshop"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -12,70 +17,339 @@ var (
 	cfg *config.Config
 )
 
+const timeFormat = "2006-01-02 15:04"
+
 var (
-	workshopPath    string
-	workshopsDir    string
 	currentWorkshop *workshop.Workshop
+	workshopSource  workshop.ResolutionSource
+	outputDir       string
+	showAll         bool
 )
 
+type statusDocument struct {
+	Workshop       *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	WorkshopSource string               `json:"workshopSource" yaml:"workshopSource"`
+	Exercise       *output.ExerciseInfo `json:"exercise,omitempty" yaml:"exercise,omitempty"`
+	Next           *output.ExerciseInfo `json:"next,omitempty" yaml:"next,omitempty"`
+	Tests          *testsInfo           `json:"tests,omitempty" yaml:"tests,omitempty"`
+	DetectionError *output.ErrorInfo    `json:"detectionError,omitempty" yaml:"detectionError,omitempty"`
+	ClosestMatch   *matchInfo           `json:"closestMatch,omitempty" yaml:"closestMatch,omitempty"`
+	Progress       *progressInfo        `json:"progress,omitempty" yaml:"progress,omitempty"`
+}
+
+type matchInfo struct {
+	Exercise    *output.ExerciseInfo `json:"exercise" yaml:"exercise"`
+	Confidence  float64              `json:"confidence" yaml:"confidence"`
+	Explanation string               `json:"explanation" yaml:"explanation"`
+}
+
+type progressInfo struct {
+	Sections  []sectionProgress `json:"sections" yaml:"sections"`
+	Saved     int               `json:"saved" yaml:"saved"`
+	Total     int               `json:"total" yaml:"total"`
+	LastSave  *time.Time        `json:"lastSave,omitempty" yaml:"lastSave,omitempty"`
+	Completed float64           `json:"completed" yaml:"completed"`
+}
+
+type sectionProgress struct {
+	Number    int                `json:"number" yaml:"number"`
+	Slug      string             `json:"slug" yaml:"slug"`
+	Exercises []exerciseProgress `json:"exercises" yaml:"exercises"`
+	Saved     int                `json:"saved" yaml:"saved"`
+	Completed float64            `json:"completed" yaml:"completed"`
+}
+
+type exerciseProgress struct {
+	Number       int        `json:"number" yaml:"number"`
+	Slug         string     `json:"slug" yaml:"slug"`
+	Current      bool       `json:"current" yaml:"current"`
+	SavedAt      *time.Time `json:"savedAt,omitempty" yaml:"savedAt,omitempty"`
+	LastActivity *time.Time `json:"lastActivity,omitempty" yaml:"lastActivity,omitempty"`
+	Tests        *testsInfo `json:"tests,omitempty" yaml:"tests,omitempty"`
+}
+
+// testsInfo is the result of the last test run of an exercise.
+type testsInfo struct {
+	Passed  bool            `json:"passed" yaml:"passed"`
+	Summary string          `json:"summary" yaml:"summary"`
+	Counts  *testrun.Counts `json:"counts,omitempty" yaml:"counts,omitempty"`
+	RanAt   time.Time       `json:"ranAt" yaml:"ranAt"`
+}
+
+func newTestsInfo(event *progress.Event) *testsInfo {
+	if event == nil || event.Passed == nil {
+		return nil
+	}
+	return &testsInfo{
+		Passed:  *event.Passed,
+		Summary: event.Summary,
+		Counts:  event.Counts,
+		RanAt:   event.Time,
+	}
+}
+
+// history holds the latest events of each exercise of the workshop, by exercise key.
+type history struct {
+	activity map[string]progress.Event
+	tests    map[string]progress.Event
+}
+
+func (h *history) lastTest(exercise *workshop.Exercise) *progress.Event {
+	if event, ok := h.tests[progress.ExerciseKey(exercise)]; ok {
+		return &event
+	}
+	return nil
+}
+
+func (h *history) lastActivity(exercise *workshop.Exercise) *time.Time {
+	if event, ok := h.activity[progress.ExerciseKey(exercise)]; ok {
+		return &event.Time
+	}
+	return nil
+}
+
+// historyTimeout is how long status waits for another kody process to release the progress database.
+// Status works without the history, so it doesn't wait long.
+const historyTimeout = 500 * time.Millisecond
+
+// loadHistory records the detection of the current exercise, if any, and reads the history of the workshop.
+func loadHistory(w *workshop.Workshop, current *workshop.Exercise) (*history, error) {
+	db, err := progress.OpenWithTimeout(config.DefaultProgressDBPath(cfg), historyTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	if current != nil {
+		// Recording the detection is best-effort, the history can still be read without it
+		_ = db.Record(progress.DetectionEvents(w, current)...)
+	}
+
+	h := &history{}
+	h.activity, err = db.Latest(w.Slug())
+	if err != nil {
+		return nil, err
+	}
+	h.tests, err = db.Latest(w.Slug(), progress.Tested)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Information about the current exercise",
-	Long:  `This command gives information about the current exercise based on the current playground.`,
+	Long:  `This command gives information about the current exercise based on the current playground. Use --all to get an overview of the progress on the whole workshop.`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		return checkAndSetupConfigs(cmd)
+		return output.WithCode(output.CodeWorkshopNotFound, checkAndSetupConfigs(cmd))
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var w *workshop.Workshop
-		var err error
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		doc := statusDocument{Workshop: output.NewWorkshopInfo(w), WorkshopSource: string(workshopSource)}
+		out.Printf("Using workshop '%s' at '%s' (from %s)\n", w.Slug(), w.Path, workshopSource)
+		for _, warning := range w.Warnings() {
+			out.Printf("Warning: %s\n", warning)
+		}
 
-		// Use the already loaded workshop if available, otherwise load it from path
-		if currentWorkshop != nil {
-			w = currentWorkshop
+		exercise, err := w.PlaygroundExercise()
+		h, historyErr := loadHistory(w, exercise)
+		if historyErr != nil {
+			// The history only adds information, status works without it
+			out.Printf("Warning: could not read progress history: %v\n", historyErr)
+			h = &history{}
+		}
+		if errors.Is(err, workshop.ErrNoExactMatch) {
+			doc.DetectionError = &output.ErrorInfo{Code: output.CodeExerciseNotDetected, Message: err.Error()}
+			doc.ClosestMatch, err = explainFuzzyMatch(out, w, err)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
+			if !showAll {
+				return output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("getting playground exercise: %w", err))
+			}
+			doc.DetectionError = &output.ErrorInfo{Code: output.CodeExerciseNotDetected, Message: err.Error()}
+			out.Printf("Could not detect the current exercise: %v\n", err)
 		} else {
-			w, err = workshop.WorkshopFromPath(workshopPath)
+			doc.Exercise = output.NewExerciseInfo(exercise)
+			out.Printf("Looks like you are doing exercise %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
+			if exercise.IsSolution() {
+				out.Println("The playground is set to the official solution of this exercise.")
+			}
+			if doc.Tests = newTestsInfo(h.lastTest(exercise)); doc.Tests != nil {
+				out.Printf("Last test run: %s (%s)\n", doc.Tests.Summary, doc.Tests.RanAt.Format(timeFormat))
+			}
+
+			outline, err := w.Outline()
 			if err != nil {
-				return fmt.Errorf("getting workshop from path '%s': %w", workshopPath, err)
+				return fmt.Errorf("loading workshop: %w", err)
+			}
+			if next := outline.Next(outline.Find(exercise)); next != nil {
+				doc.Next = output.NewExerciseInfo(next.Exercise)
+				out.Printf("Next exercise is %s\n", next.BreadCrumbs())
 			}
 		}
 
-		exercise, err := w.PlaygroundExercise()
-		if err != nil {
-			return fmt.Errorf("getting playground exercise: %w", err)
+		if showAll {
+			doc.Progress, err = workshopProgress(w, exercise, h)
+			if err != nil {
+				return err
+			}
+			out.Println()
+			printProgress(out, w, doc.Progress)
 		}
 
-		fmt.Printf("Looks like you are doing exercise %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
-
-		return nil
+		return out.Document(doc)
 	},
 }
 
-func checkAndSetupConfigs(cmd *cobra.Command) error {
-	workshopPath = cfg.GetString("workshop.path")
-	workshopsDir = cfg.GetString("workshops.dir")
+func workshopProgress(w *workshop.Workshop, current *workshop.Exercise, h *history) (*progressInfo, error) {
+	exercises, err := w.Exercises()
+	if err != nil {
+		return nil, fmt.Errorf("listing exercises: %w", err)
+	}
 
-	// Check if flags were passed directly
-	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
-		workshopPath = workshopPathFlag.Value.String()
+	progress := &progressInfo{Total: len(exercises)}
+
+	for _, exercise := range exercises {
+		savedAt, err := workshop.SavedExerciseTime(outputDir, w, exercise)
+		if err != nil {
+			return nil, fmt.Errorf("checking saved solution for exercise %s: %w", exercise.BreadCrumbs(), err)
+		}
+
+		if len(progress.Sections) == 0 || progress.Sections[len(progress.Sections)-1].Number != exercise.Section.Number {
+			progress.Sections = append(progress.Sections, sectionProgress{
+				Number: exercise.Section.Number,
+				Slug:   exercise.Section.Slug,
+			})
+		}
+		section := &progress.Sections[len(progress.Sections)-1]
+
+		section.Exercises = append(section.Exercises, exerciseProgress{
+			Number:       exercise.Number,
+			Slug:         exercise.Slug,
+			Current:      current != nil && current.Section.Number == exercise.Section.Number && current.Number == exercise.Number,
+			SavedAt:      savedAt,
+			LastActivity: h.lastActivity(exercise),
+			Tests:        newTestsInfo(h.lastTest(exercise)),
+		})
+
+		if savedAt != nil {
+			section.Saved++
+			progress.Saved++
+			if progress.LastSave == nil || savedAt.After(*progress.LastSave) {
+				progress.LastSave = savedAt
+			}
+		}
 	}
-	if workshopsDirFlag := cmd.Flags().Lookup("workshops-dir"); workshopsDirFlag != nil && workshopsDirFlag.Changed {
-		workshopsDir = workshopsDirFlag.Value.String()
+
+	for i := range progress.Sections {
+		progress.Sections[i].Completed = ratio(progress.Sections[i].Saved, len(progress.Sections[i].Exercises))
 	}
+	progress.Completed = ratio(progress.Saved, progress.Total)
 
-	// If workshopPath is not provided but workshopsDir is, auto-detect the current workshop
-	if workshopPath == "" && workshopsDir != "" {
-		var err error
-		currentWorkshop, err = workshop.DetectCurrentWorkshop(workshopsDir)
-		if err != nil {
-			return fmt.Errorf("auto-detecting workshop from workshopsDir '%s': %w", workshopsDir, err)
+	return progress, nil
+}
+
+func printProgress(out *output.Printer, w *workshop.Workshop, progress *progressInfo) {
+	if progress.Total == 0 {
+		out.Printf("No exercises found in workshop '%s'\n", w.Path)
+		return
+	}
+
+	out.Printf("%s (%s)\n", w.AsciiTitle(), w.Slug())
+
+	for _, section := range progress.Sections {
+		out.Printf("\n[%0.2d] %s (%d/%d saved, %.0f%%)\n", section.Number, section.Slug, section.Saved, len(section.Exercises), section.Completed*100)
+
+		for _, exercise := range section.Exercises {
+			currentMarker := " "
+			if exercise.Current {
+				currentMarker = ">"
+			}
+
+			savedMarker := " "
+			savedInfo := ""
+			if exercise.SavedAt != nil {
+				savedMarker = "x"
+				savedInfo = fmt.Sprintf(" (saved %s)", exercise.SavedAt.Format(timeFormat))
+			} else if exercise.LastActivity != nil {
+				savedInfo = fmt.Sprintf(" (last activity %s)", exercise.LastActivity.Format(timeFormat))
+			}
+
+			testsInfo := ""
+			if exercise.Tests != nil && exercise.Tests.Passed {
+				testsInfo = " - tests passing"
+			} else if exercise.Tests != nil {
+				testsInfo = " - tests failing"
+			}
+
+			out.Printf(" %s [%s] [%0.2d] %s%s%s\n", currentMarker, savedMarker, exercise.Number, exercise.Slug, savedInfo, testsInfo)
 		}
-		workshopPath = currentWorkshop.Path
 	}
 
-	if workshopPath == "" {
-		return fmt.Errorf("please provide a path to the workshop folder using the --workshop flag or the workshop.path configuration, or use --workshops-dir to auto-detect")
+	out.Printf("\nCompletion: %d/%d exercises saved (%.0f%%)\n", progress.Saved, progress.Total, progress.Completed*100)
+	if progress.LastSave != nil {
+		out.Printf("Last save: %s\n", progress.LastSave.Format(timeFormat))
+	}
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func explainFuzzyMatch(out *output.Printer, w *workshop.Workshop, exactErr error) (*matchInfo, error) {
+	out.Printf("Could not detect the exercise exactly: %v\n", exactErr)
+	out.Println("The playground README.mdx does not match the README.mdx of any exercise byte for byte. This usually happens after a workshop update or an edit to the playground README.mdx.")
+
+	match, err := w.BestPlaygroundMatch()
+	if err != nil {
+		return nil, fmt.Errorf("matching playground against exercises: %w", err)
+	}
+
+	if match == nil {
+		out.Println("No exercises found in the workshop to compare the playground with.")
+		return nil, nil
 	}
 
+	out.Printf("Closest exercise is %s with %.0f%% confidence (%s)\n", match.Exercise.BreadCrumbsWithWorkshop(w.Slug()), match.Confidence*100, match.Explain())
+
+	return &matchInfo{
+		Exercise:    output.NewExerciseInfo(match.Exercise),
+		Confidence:  match.Confidence,
+		Explanation: match.Explain(),
+	}, nil
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+	workshopSource = resolution.Source
+
 	return nil
 }
 
@@ -84,6 +358,13 @@ func GetCmd(config *config.Config) *cobra.Command {
 
 	cfg.BindFlagConfigToCommand("workshop.dir", statusCmd)
 	cfg.BindFlagConfigToCommand("workshops.dir", statusCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", statusCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", statusCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", statusCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", statusCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", statusCmd)
+
+	statusCmd.Flags().BoolVarP(&showAll, "all", "a", false, "List every section and exercise of the workshop, marking the saved ones and the current one")
 
 	return statusCmd
 }
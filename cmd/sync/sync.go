@@ -0,0 +1,220 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/prompt"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop       *workshop.Workshop
+	outputDir             string
+	shouldCommit          bool
+	requireTests          bool
+	indexFile             string
+	commitMessageTemplate *template.Template
+)
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+	shouldCommit = cfg.GetBool("save.shouldCommit")
+	requireTests = cfg.GetBool("save.requireTests")
+	indexFile = cfg.GetString("save.index")
+	commitMessageTemplateString := cfg.GetString("save.commit.message")
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+
+	if outputDir == "" {
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
+	}
+
+	commitMessageTemplate, err = template.New("commitMessage").Parse(commitMessageTemplateString)
+	if err != nil {
+		return fmt.Errorf("parsing commit message template: %w", err)
+	}
+
+	return nil
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Save the previous exercise when the playground was switched to another one",
+	Long: `Compare the playground with the last state kody knows about. If the playground was switched to another exercise since then, the work on the previous exercise is saved from the copy kody kept of it, and if you have a saved solution for the new exercise, kody offers to restore it.
+
+Run it every time before switching exercises, or use kody watch --sync to do it automatically.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkAndSetupConfigs(cmd); err != nil {
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: %w", err))
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		// Set when the switch was already announced before asking to restore
+		announced := false
+		syncOpts := solutions.SyncOptions{
+			StateDir: config.DefaultPlaygroundStateDir(cfg),
+			Save: solutions.SaveOptions{
+				OutputDir:     outputDir,
+				Commit:        shouldCommit,
+				CommitMessage: commitMessageTemplate,
+				IndexFile:     indexFile,
+			},
+			ConfirmRestore: func(exercise *workshop.Exercise, savedPath string) (bool, error) {
+				if yes {
+					return true, nil
+				}
+				if !out.IsText() {
+					// Without --yes, structured output only reports the saved solution
+					return false, nil
+				}
+				announced = true
+				out.Printf("The playground was switched to %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
+				return prompt.Confirm(fmt.Sprintf("You have a saved solution for %s in '%s'. Restore it to the playground?", exercise.BreadCrumbs(), savedPath))
+			},
+		}
+		if requireTests {
+			syncOpts.CheckTests = func(exercise *workshop.Exercise) error {
+				return progress.CheckTestsPassed(config.DefaultProgressDBPath(cfg), w, exercise)
+			}
+		}
+		result, err := solutions.Sync(w, syncOpts)
+		if result.Saved != nil {
+			out.Print(result.Saved.GitOutput)
+		}
+		if recordErr := progress.Record(config.DefaultProgressDBPath(cfg), progress.SyncEvents(w, result)...); recordErr != nil {
+			out.Printf("Warning: could not record progress: %v\n", recordErr)
+		}
+		if err != nil {
+			if result.Exercise == nil {
+				return output.WithCode(output.CodeExerciseNotDetected, err)
+			}
+			var saveErr *solutions.SaveError
+			if errors.As(err, &saveErr) && saveErr.Step == solutions.CommitStep {
+				return output.WithCode(output.CodeCommitFailed, err)
+			}
+			return output.WithCode(output.CodeCopyFailed, err)
+		}
+
+		doc := newSyncDocument(w, result)
+
+		for _, warning := range result.Warnings {
+			out.Printf("Warning: %s\n", warning)
+		}
+
+		switch {
+		case result.Previous == nil:
+			out.Printf("Started tracking the playground, set to %s\n", result.Exercise.BreadCrumbsWithWorkshop(w.Slug()))
+		case !result.Switched:
+			out.Printf("The playground is still set to %s, its state was updated\n", result.Exercise.BreadCrumbsWithWorkshop(w.Slug()))
+		default:
+			if !announced {
+				out.Printf("The playground was switched from %s to %s\n", result.Previous.BreadCrumbs, result.Exercise.BreadCrumbs())
+			}
+			if result.Saved != nil {
+				out.Printf("Saved your work on %s to '%s'\n", result.PreviousExercise.BreadCrumbs(), result.Saved.Destination)
+			}
+			if result.Restored {
+				out.Printf("Restored your saved solution from '%s'\n", result.SavedSolution)
+			} else if result.SavedSolution != "" {
+				out.Printf("You have a saved solution for %s in '%s', use kody restore to restore it\n", result.Exercise.BreadCrumbs(), result.SavedSolution)
+			}
+		}
+
+		return out.Document(doc)
+	},
+}
+
+type syncDocument struct {
+	Workshop         *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Exercise         *output.ExerciseInfo `json:"exercise" yaml:"exercise"`
+	Switched         bool                 `json:"switched" yaml:"switched"`
+	PreviousExercise *output.ExerciseInfo `json:"previousExercise,omitempty" yaml:"previousExercise,omitempty"`
+	SavedTo          string               `json:"savedTo,omitempty" yaml:"savedTo,omitempty"`
+	SavedSolution    string               `json:"savedSolution,omitempty" yaml:"savedSolution,omitempty"`
+	Actions          []string             `json:"actions" yaml:"actions"`
+	CommitMessage    string               `json:"commitMessage,omitempty" yaml:"commitMessage,omitempty"`
+	Warnings         []string             `json:"warnings" yaml:"warnings"`
+}
+
+func newSyncDocument(w *workshop.Workshop, result *solutions.SyncResult) syncDocument {
+	doc := syncDocument{
+		Workshop:      output.NewWorkshopInfo(w),
+		Exercise:      output.NewExerciseInfo(result.Exercise),
+		Switched:      result.Switched,
+		SavedSolution: result.SavedSolution,
+		Actions:       []string{},
+		Warnings:      result.Warnings,
+	}
+
+	if result.PreviousExercise != nil {
+		doc.PreviousExercise = output.NewExerciseInfo(result.PreviousExercise)
+	}
+
+	if result.Saved != nil {
+		doc.SavedTo = result.Saved.Destination
+		doc.Actions = append(doc.Actions, "savedPrevious")
+		if result.Saved.Committed {
+			doc.Actions = append(doc.Actions, "committed")
+			doc.CommitMessage = result.Saved.CommitMessage
+		}
+	}
+
+	if result.Restored {
+		doc.Actions = append(doc.Actions, "restored")
+	}
+	doc.Actions = append(doc.Actions, "tracked")
+
+	return doc
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", syncCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", syncCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", syncCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", syncCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", syncCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", syncCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", syncCmd)
+	cfg.BindFlagConfigToCommand("save.shouldCommit", syncCmd)
+	cfg.BindFlagConfigToCommand("save.commit.message", syncCmd)
+	cfg.BindFlagConfigToCommand("save.requireTests", syncCmd)
+	cfg.BindFlagConfigToCommand("save.index", syncCmd)
+
+	syncCmd.Flags().BoolP("yes", "y", false, "Restore the saved solution of the new exercise without asking")
+
+	return syncCmd
+}
@@ -1,9 +1,18 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/testrun"
 	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -13,45 +22,109 @@ var (
 )
 
 var (
-	workshopPath    string
-	workshopsDir    string
 	currentWorkshop *workshop.Workshop
 )
 
+type testDocument struct {
+	Workshop *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Exercise *output.ExerciseInfo `json:"exercise,omitempty" yaml:"exercise,omitempty"`
+	Source   string               `json:"source" yaml:"source"`
+	Result   *testrun.Result      `json:"result" yaml:"result"`
+	Recorded bool                 `json:"recorded" yaml:"recorded"`
+	Error    *output.ErrorInfo    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 var testCmd = &cobra.Command{
 	Use:   "test",
-	Short: "Testing command",
+	Short: "Run the tests of the exercise in the playground",
+	Long: `Run the tests of the exercise in the playground, showing their output as they run.
+
+The tests are run with the test script of the playground package.json, or the test script of the workshop package.json, or, when there is no test script, with the test files in the playground. The result is recorded for the current exercise and shown by kody status --all.`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		return checkAndSetupConfigs(cmd)
+		return output.WithCode(output.CodeWorkshopNotFound, checkAndSetupConfigs(cmd))
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Printf("workshop path: %v\n", workshopPath)
-		fmt.Printf("workshops dir: %v\n", workshopsDir)
-		return nil
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		doc := testDocument{Workshop: output.NewWorkshopInfo(w)}
+
+		exercise, err := w.PlaygroundExercise()
+		if err != nil {
+			// The tests can still run, but the result can't be recorded
+			out.Printf("Warning: could not detect the exercise in the playground, the result will not be recorded: %v\n", err)
+		} else {
+			doc.Exercise = output.NewExerciseInfo(exercise)
+			out.Printf("Testing exercise %s\n", exercise.BreadCrumbsWithWorkshop(w.Slug()))
+		}
+
+		command, err := testrun.DetectCommand(w)
+		if err != nil {
+			return output.WithCode(output.CodeTestsNotFound, err)
+		}
+		doc.Source = string(command.Source)
+		out.Printf("Running '%s' in '%s' (from the %s)\n\n", command, command.Dir, command.Source)
+
+		// Structured outputs are written to stdout, so the output of the tests goes to stderr
+		var stream io.Writer = os.Stdout
+		if !out.IsText() {
+			stream = os.Stderr
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		result, err := testrun.Run(ctx, command, stream)
+		if err != nil {
+			return err
+		}
+		doc.Result = result
+
+		out.Printf("\n%s in %s\n", result.Summary(), result.Duration.Round(100*time.Millisecond))
+
+		if exercise != nil {
+			events := append(progress.DetectionEvents(w, exercise), progress.NewTestEvent(w, exercise, result))
+			err := progress.Record(config.DefaultProgressDBPath(cfg), events...)
+			if err != nil {
+				return fmt.Errorf("recording test result: %w", err)
+			}
+			doc.Recorded = true
+		}
+
+		if result.Passed {
+			return out.Document(doc)
+		}
+
+		// Failing tests are not a usage error
+		cmd.SilenceUsage = true
+		failedErr := output.WithCode(output.CodeTestsFailed, errors.New(result.Summary()))
+		doc.Error = output.NewErrorInfo(failedErr)
+		if err := out.Document(doc); err != nil {
+			return err
+		}
+		return output.Reported(failedErr)
 	},
 }
 
 func checkAndSetupConfigs(cmd *cobra.Command) error {
-	workshopPath = cfg.GetString("workshop.path")
-	workshopsDir = cfg.GetString("workshops.dir")
 
-	// Check if flags were passed directly
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
 	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
-		workshopPath = workshopPathFlag.Value.String()
-	}
-	if workshopsDirFlag := cmd.Flags().Lookup("workshops-dir"); workshopsDirFlag != nil && workshopsDirFlag.Changed {
-		workshopsDir = workshopsDirFlag.Value.String()
+		flagPath = workshopPathFlag.Value.String()
 	}
 
-	// If workshopPath is not provided but workshopsDir is, auto-detect the current workshop
-	if workshopPath == "" && workshopsDir != "" {
-		var err error
-		currentWorkshop, err = workshop.DetectCurrentWorkshop(workshopsDir)
-		if err != nil {
-			return fmt.Errorf("auto-detecting workshop from workshopsDir '%s': %w", workshopsDir, err)
-		}
-		workshopPath = currentWorkshop.Path
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
 	}
+	currentWorkshop = resolution.Workshop
 
 	return nil
 }
@@ -61,6 +134,10 @@ func GetCmd(config *config.Config) *cobra.Command {
 
 	cfg.BindFlagConfigToCommand("workshop.dir", testCmd)
 	cfg.BindFlagConfigToCommand("workshops.dir", testCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", testCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", testCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", testCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", testCmd)
 
 	return testCmd
 }
@@ -0,0 +1,259 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/testrun"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+var (
+	currentWorkshop *workshop.Workshop
+	outputDir       string
+	verifyAll       bool
+	verbose         bool
+	keepSandbox     bool
+	sectionNo       int
+	exerciseNo      int
+)
+
+type verifyDocument struct {
+	Workshop  *output.WorkshopInfo    `json:"workshop" yaml:"workshop"`
+	Sandbox   string                  `json:"sandbox" yaml:"sandbox"`
+	Exercises []exerciseVerification  `json:"exercises" yaml:"exercises"`
+	Summary   solutions.VerifySummary `json:"summary" yaml:"summary"`
+	Error     *output.ErrorInfo       `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type exerciseVerification struct {
+	Exercise  *output.ExerciseInfo `json:"exercise" yaml:"exercise"`
+	SavedPath string               `json:"savedPath" yaml:"savedPath"`
+	Passed    bool                 `json:"passed" yaml:"passed"`
+	Tests     *testrun.Result      `json:"tests,omitempty" yaml:"tests,omitempty"`
+	Error     string               `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+	if err != nil {
+		return err
+	}
+	currentWorkshop = resolution.Workshop
+
+	if outputDir == "" {
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
+	}
+
+	return nil
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [exercise]",
+	Short: "Run the tests of your saved solutions",
+	Long: `Run the tests of your saved solutions against the current version of the workshop, to catch solutions that broke after a workshop update.
+
+The workshop is copied to a temporary sandbox and each saved solution is tested in the playground of the sandbox, so your real playground is never touched. Without arguments, the saved solution of the exercise in the playground is verified. Pass an exercise in the format <section_number>.<exercise_number>, e.g. "01.02", to verify another one, or --all to verify all of them.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkAndSetupConfigs(cmd); err != nil {
+			return output.WithCode(output.CodeInvalidConfig, fmt.Errorf("flag error: %w", err))
+		}
+		return nil
+	},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+
+		if len(args) != 1 || verifyAll {
+			return errors.New("verify accepts either --all or at most one argument with the exercise to verify, in the format <section_number>.<exercise_number>, e.g. \"01.02\"")
+		}
+
+		exerciseSplit := strings.Split(args[0], ".")
+		if len(exerciseSplit) != 2 {
+			return errors.New("exercise argument must be in the format <section_number>.<exercise_number>, e.g. \"01.02\"")
+		}
+		var err error
+		sectionNo, err = strconv.Atoi(exerciseSplit[0])
+		if err != nil {
+			return fmt.Errorf("parsing section number: %w", err)
+		}
+
+		exerciseNo, err = strconv.Atoi(exerciseSplit[1])
+		if err != nil {
+			return fmt.Errorf("parsing exercise number: %w", err)
+		}
+
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		w := currentWorkshop
+
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		exercises, err := exercisesToVerify(w, args)
+		if err != nil {
+			return err
+		}
+
+		sandbox, err := solutions.NewSandbox(w)
+		if err != nil {
+			return err
+		}
+		if keepSandbox {
+			out.Printf("Keeping the sandbox at '%s'\n", sandbox.Workshop.Path)
+		} else {
+			defer sandbox.Remove()
+		}
+
+		doc := verifyDocument{
+			Workshop:  output.NewWorkshopInfo(w),
+			Sandbox:   sandbox.Workshop.Path,
+			Exercises: []exerciseVerification{},
+		}
+		out.Printf("Verifying the saved solutions of %s in a sandbox\n\n", w.Slug())
+
+		// Structured outputs are written to stdout, so the output of the tests goes to stderr
+		var testOutput io.Writer
+		if verbose && out.IsText() {
+			testOutput = os.Stdout
+		} else if verbose {
+			testOutput = os.Stderr
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		results, err := sandbox.Verify(ctx, exercises, solutions.VerifyOptions{
+			OutputDir:  outputDir,
+			TestOutput: testOutput,
+			OnResult: func(result *solutions.VerifyResult) {
+				printResult(out, result)
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("verifying saved solutions: %w", err)
+		}
+
+		for _, result := range results {
+			verification := exerciseVerification{
+				Exercise:  output.NewExerciseInfo(result.Exercise),
+				SavedPath: result.SavedPath,
+				Passed:    result.Passed(),
+				Tests:     result.Tests,
+			}
+			if result.Err != nil {
+				verification.Error = result.Err.Error()
+			}
+			doc.Exercises = append(doc.Exercises, verification)
+		}
+		doc.Summary = solutions.SummarizeVerify(results)
+
+		if len(results) == 0 {
+			out.Println("No saved solutions to verify.")
+		} else {
+			out.Printf("\nVerified %d saved solutions in %s: %d passed, %d failed\n", len(results), doc.Summary.Duration.Round(100*time.Millisecond), doc.Summary.Passed, doc.Summary.Failed)
+		}
+
+		if doc.Summary.Failed == 0 {
+			return out.Document(doc)
+		}
+
+		// Failing solutions are not a usage error
+		cmd.SilenceUsage = true
+		failedErr := output.WithCode(output.CodeTestsFailed, fmt.Errorf("%d of %d saved solutions failed verification", doc.Summary.Failed, len(results)))
+		doc.Error = output.NewErrorInfo(failedErr)
+		if err := out.Document(doc); err != nil {
+			return err
+		}
+		return output.Reported(failedErr)
+	},
+}
+
+func exercisesToVerify(w *workshop.Workshop, args []string) ([]*workshop.Exercise, error) {
+	exercises, err := w.Exercises()
+	if err != nil {
+		return nil, fmt.Errorf("listing exercises: %w", err)
+	}
+
+	if verifyAll {
+		return exercises, nil
+	}
+
+	if len(args) == 0 {
+		playgroundExercise, err := w.PlaygroundExercise()
+		if err != nil {
+			return nil, output.WithCode(output.CodeExerciseNotDetected, fmt.Errorf("auto-detecting exercise from playground: %w", err))
+		}
+		sectionNo = playgroundExercise.Section.Number
+		exerciseNo = playgroundExercise.Number
+	}
+
+	for _, exercise := range exercises {
+		if exercise.Section.Number == sectionNo && exercise.Number == exerciseNo {
+			if _, err := solutions.FindSaved(outputDir, w, sectionNo, exerciseNo); err != nil {
+				return nil, output.WithCode(output.CodeSavedExerciseMissing, err)
+			}
+			return []*workshop.Exercise{exercise}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("exercise %02d.%02d not found in workshop '%s'", sectionNo, exerciseNo, w.Slug())
+}
+
+func printResult(out *output.Printer, result *solutions.VerifyResult) {
+	switch {
+	case result.Err != nil:
+		out.Printf("ERROR %s: %v\n", result.Exercise.BreadCrumbs(), result.Err)
+	case result.Passed():
+		out.Printf("PASS  %s (%s)\n", result.Exercise.BreadCrumbs(), result.Tests.Summary())
+	default:
+		out.Printf("FAIL  %s (%s)\n", result.Exercise.BreadCrumbs(), result.Tests.Summary())
+	}
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", verifyCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", verifyCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", verifyCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", verifyCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", verifyCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", verifyCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", verifyCmd)
+
+	verifyCmd.Flags().BoolVarP(&verifyAll, "all", "a", false, "Verify the saved solutions of all the exercises of the workshop")
+	verifyCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show the output of the tests")
+	verifyCmd.Flags().BoolVar(&keepSandbox, "keep", false, "Don't remove the sandbox after verifying, to inspect it")
+
+	return verifyCmd
+}
@@ -1,8 +1,8 @@
 package version
 
 import (
-	"fmt"
 	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
 
 	"github.com/spf13/cobra"
 )
@@ -11,22 +11,33 @@ var (
 	cfg *config.Config
 )
 
+type versionDocument struct {
+	Version string `json:"version" yaml:"version"`
+	Commit  string `json:"commit" yaml:"commit"`
+	Date    string `json:"date" yaml:"date"`
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Display version information",
 	Long:  `Display the version information for kody. Use -v flag for verbose output including commit and date.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
 		buildInfo := cfg.GetBuildInfo()
 
 		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
-			fmt.Printf("Version: %s\n", buildInfo.Version)
-			fmt.Printf("Commit:  %s\n", buildInfo.Commit)
-			fmt.Printf("Date:    %s\n", buildInfo.Date)
+			out.Printf("Version: %s\n", buildInfo.Version)
+			out.Printf("Commit:  %s\n", buildInfo.Commit)
+			out.Printf("Date:    %s\n", buildInfo.Date)
 		} else {
-			fmt.Println(buildInfo.Version)
+			out.Println(buildInfo.Version)
 		}
 
-		return nil
+		return out.Document(versionDocument{
+			Version: buildInfo.Version,
+			Commit:  buildInfo.Commit,
+			Date:    buildInfo.Date,
+		})
 	},
 }
 
@@ -0,0 +1,366 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/prompt"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/testrun"
+	"github.com/andrerfcsantos/kody/lib/watch"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"os/signal"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+const timeFormat = "15:04:05"
+
+var (
+	workshops             []*workshop.Workshop
+	outputDir             string
+	shouldCommit          bool
+	requireTests          bool
+	indexFile             string
+	commitMessageTemplate *template.Template
+	debounce              time.Duration
+	syncPlayground        bool
+	restoreWithoutAsking  bool
+)
+
+type saveEvent struct {
+	Time          time.Time            `json:"time" yaml:"time"`
+	Workshop      *output.WorkshopInfo `json:"workshop" yaml:"workshop"`
+	Exercise      *output.ExerciseInfo `json:"exercise,omitempty" yaml:"exercise,omitempty"`
+	Destination   string               `json:"destination,omitempty" yaml:"destination,omitempty"`
+	Actions       []string             `json:"actions" yaml:"actions"`
+	CommitMessage string               `json:"commitMessage,omitempty" yaml:"commitMessage,omitempty"`
+	Tests         *testrun.Result      `json:"tests,omitempty" yaml:"tests,omitempty"`
+	// Only set with --sync, when the playground was switched to another exercise
+	PreviousExercise *output.ExerciseInfo `json:"previousExercise,omitempty" yaml:"previousExercise,omitempty"`
+	SavedSolution    string               `json:"savedSolution,omitempty" yaml:"savedSolution,omitempty"`
+	Warnings         []string             `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Error            *output.ErrorInfo    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the playground and save it automatically",
+	Long: `Watch the playground of the current workshop and save it automatically once the changes settle. When the workshop is auto-detected, the playgrounds of all the workshops are watched, so the saves follow you across workshops. Changes to dependencies, build output, caches and hidden files are ignored.
+
+With --sync, kody also keeps the last known state of the playground, like kody sync does. When the playground is switched to another exercise, the previous exercise is saved from that state instead of saving the new playground, and kody offers to restore your saved solution of the new exercise.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.WithCode(output.CodeInvalidConfig, checkAndSetupConfigs(cmd))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		for _, w := range workshops {
+			err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+			if err != nil {
+				return fmt.Errorf("loading exercise hash index: %w", err)
+			}
+		}
+
+		watcher, err := watch.New(workshops, debounce)
+		if err != nil {
+			return err
+		}
+
+		if syncPlayground {
+			// Catch up with the switches that happened while kody wasn't watching
+			for _, w := range workshops {
+				exercise, err := w.PlaygroundExercise()
+				if err != nil {
+					continue
+				}
+
+				event := saveEvent{
+					Time:     time.Now(),
+					Workshop: output.NewWorkshopInfo(w),
+					Exercise: output.NewExerciseInfo(exercise),
+					Actions:  []string{},
+				}
+				prefix := fmt.Sprintf("[%s] %s:", event.Time.Format(timeFormat), w.Slug())
+				history, _ := sync(out, w, prefix, &event)
+				if err := progress.Record(config.DefaultProgressDBPath(cfg), history...); err != nil {
+					out.Printf("%s warning: could not record progress: %v\n", prefix, err)
+				}
+				if err := out.Document(event); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, w := range workshops {
+			out.Printf("Watching '%s'\n", w.PlaygroundPath())
+		}
+		out.Println("Press Ctrl+C to stop.")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		return watcher.Run(ctx, func(w *workshop.Workshop) {
+			event := autosave(out, w)
+			if err := out.Document(event); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}, func(err error) {
+			fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		})
+	},
+}
+
+func autosave(out *output.Printer, w *workshop.Workshop) saveEvent {
+	event := saveEvent{
+		Time:     time.Now(),
+		Workshop: output.NewWorkshopInfo(w),
+		Actions:  []string{},
+	}
+	prefix := fmt.Sprintf("[%s] %s:", event.Time.Format(timeFormat), w.Slug())
+
+	exercise, err := w.PlaygroundExercise()
+	if err != nil {
+		out.Printf("%s not saving, could not detect the exercise: %v\n", prefix, err)
+		event.Error = &output.ErrorInfo{Code: output.CodeExerciseNotDetected, Message: err.Error()}
+		return event
+	}
+	event.Exercise = output.NewExerciseInfo(exercise)
+
+	history := progress.DetectionEvents(w, exercise)
+	defer func() {
+		if err := progress.Record(config.DefaultProgressDBPath(cfg), history...); err != nil {
+			out.Printf("%s warning: could not record progress: %v\n", prefix, err)
+		}
+	}()
+
+	if syncPlayground {
+		var switched bool
+		history, switched = sync(out, w, prefix, &event)
+		if switched || event.Error != nil {
+			// The new playground holds the starting files of the exercise, there is nothing to save yet
+			return event
+		}
+	}
+
+	if exercise.IsSolution() {
+		out.Printf("%s not saving %s, the playground is set to the official solution\n", prefix, exercise.BreadCrumbs())
+		event.Error = &output.ErrorInfo{Code: output.CodeConfirmationRequired, Message: "the playground is set to the official solution of the exercise"}
+		return event
+	}
+
+	saveOpts := solutions.SaveOptions{
+		OutputDir:     outputDir,
+		Commit:        shouldCommit,
+		CommitMessage: commitMessageTemplate,
+		RequireTests:  requireTests,
+		IndexFile:     indexFile,
+	}
+	result, err := solutions.Save(w, exercise, saveOpts)
+	for _, warning := range result.Warnings {
+		out.Printf("%s warning: %s\n", prefix, warning)
+	}
+	event.Warnings = result.Warnings
+	event.Destination = result.Destination
+	event.Tests = result.Tests
+	if result.Tests != nil {
+		history = append(history, progress.NewTestEvent(w, exercise, result.Tests))
+	}
+	if result.Copied {
+		saved := progress.NewEvent(progress.Saved, w, exercise)
+		saved.Path = result.Destination
+		history = append(history, saved)
+	}
+	if err != nil {
+		out.Printf("%s saving %s failed: %v\n", prefix, exercise.BreadCrumbs(), err)
+		event.Error = &output.ErrorInfo{Code: output.CodeCopyFailed, Message: err.Error()}
+		var saveErr *solutions.SaveError
+		if result.Copied {
+			event.Actions = append(event.Actions, "copied")
+		}
+		if errors.As(err, &saveErr) && saveErr.Step == solutions.CommitStep {
+			event.Error.Code = output.CodeCommitFailed
+		}
+		if errors.As(err, &saveErr) && saveErr.Step == solutions.TestStep {
+			event.Error.Code = output.CodeTestsFailed
+			if errors.Is(err, testrun.ErrNoTests) {
+				event.Error.Code = output.CodeTestsNotFound
+			}
+		}
+		return event
+	}
+
+	event.Actions = append(event.Actions, "copied")
+	if result.Committed {
+		event.Actions = append(event.Actions, "committed")
+		event.CommitMessage = result.CommitMessage
+		out.Printf("%s saved and committed %s\n", prefix, exercise.BreadCrumbs())
+	} else {
+		out.Printf("%s saved %s\n", prefix, exercise.BreadCrumbs())
+	}
+
+	return event
+}
+
+// sync saves the previous exercise and offers to restore the saved solution of the current one when the
+// playground was switched, and records the playground as its last known state. Returns the events to
+// record in the progress history and whether the playground was switched.
+func sync(out *output.Printer, w *workshop.Workshop, prefix string, event *saveEvent) ([]progress.Event, bool) {
+	syncOpts := solutions.SyncOptions{
+		StateDir: config.DefaultPlaygroundStateDir(cfg),
+		Save: solutions.SaveOptions{
+			OutputDir:     outputDir,
+			Commit:        shouldCommit,
+			CommitMessage: commitMessageTemplate,
+			IndexFile:     indexFile,
+		},
+		ConfirmRestore: func(exercise *workshop.Exercise, savedPath string) (bool, error) {
+			if restoreWithoutAsking {
+				return true, nil
+			}
+			if !out.IsText() {
+				return false, nil
+			}
+			return prompt.Confirm(fmt.Sprintf("%s you have a saved solution for %s in '%s'. Restore it to the playground?", prefix, exercise.BreadCrumbs(), savedPath))
+		},
+	}
+	if requireTests {
+		// The previous exercise is saved from a copy of the playground, it can only rely on the tests that already ran
+		syncOpts.CheckTests = func(exercise *workshop.Exercise) error {
+			return progress.CheckTestsPassed(config.DefaultProgressDBPath(cfg), w, exercise)
+		}
+	}
+	result, err := solutions.Sync(w, syncOpts)
+
+	for _, warning := range result.Warnings {
+		out.Printf("%s warning: %s\n", prefix, warning)
+	}
+	event.Warnings = result.Warnings
+	event.SavedSolution = result.SavedSolution
+	if result.PreviousExercise != nil {
+		event.PreviousExercise = output.NewExerciseInfo(result.PreviousExercise)
+	}
+
+	if result.Saved != nil {
+		event.Destination = result.Saved.Destination
+		event.Actions = append(event.Actions, "savedPrevious")
+		if result.Saved.Committed {
+			event.Actions = append(event.Actions, "committed")
+			event.CommitMessage = result.Saved.CommitMessage
+		}
+		out.Printf("%s the playground was switched, saved your work on %s\n", prefix, result.PreviousExercise.BreadCrumbs())
+	}
+
+	if err != nil {
+		out.Printf("%s syncing the playground failed: %v\n", prefix, err)
+		event.Error = &output.ErrorInfo{Code: output.CodeCopyFailed, Message: err.Error()}
+		return progress.SyncEvents(w, result), result.Switched
+	}
+
+	if result.Restored {
+		event.Actions = append(event.Actions, "restored")
+		out.Printf("%s restored your saved solution of %s\n", prefix, result.Exercise.BreadCrumbs())
+	} else if result.Switched && result.SavedSolution == "" {
+		out.Printf("%s the playground was switched to %s\n", prefix, result.Exercise.BreadCrumbs())
+	}
+
+	return progress.SyncEvents(w, result), result.Switched
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	outputDir = cfg.GetString("save.output.directory")
+	shouldCommit = cfg.GetBool("save.shouldCommit")
+	requireTests = cfg.GetBool("save.requireTests")
+	indexFile = cfg.GetString("save.index")
+	commitMessageTemplateString := cfg.GetString("save.commit.message")
+	syncPlayground = cfg.GetBool("watch.sync")
+	restoreWithoutAsking, _ = cmd.Flags().GetBool("yes")
+
+	var err error
+	debounce, err = time.ParseDuration(cfg.GetString("watch.debounce"))
+	if err != nil {
+		return fmt.Errorf("parsing watch.debounce: %w", err)
+	}
+
+	// The --workshop flag is bound to the workshop.dir configuration, check if it was passed directly
+	var flagPath string
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+
+	opts := workshop.ResolveOptionsFromConfig(cfg, flagPath)
+	resolution, err := workshop.Resolve(opts)
+	if err != nil {
+		return err
+	}
+
+	workshops = []*workshop.Workshop{resolution.Workshop}
+	if resolution.Source == workshop.FromAutoDetection {
+		// Follow the playground across all the workshops
+		workshops, err = loadWorkshops(opts.Search)
+		if err != nil {
+			return err
+		}
+	}
+
+	if outputDir == "" {
+		return errors.New("please provide a path to the output directory using the --output-dir flag or the save.output.directory configuration")
+	}
+
+	commitMessageTemplate, err = template.New("commitMessage").Parse(commitMessageTemplateString)
+	if err != nil {
+		return fmt.Errorf("parsing commit message template: %w", err)
+	}
+
+	return nil
+}
+
+func loadWorkshops(opts workshop.SearchOptions) ([]*workshop.Workshop, error) {
+	paths, err := workshop.FindWorkshops(opts)
+	if err != nil {
+		return nil, fmt.Errorf("finding workshops: %w", err)
+	}
+
+	var loaded []*workshop.Workshop
+	for _, path := range paths {
+		w, err := workshop.WorkshopFromPath(path)
+		if err != nil {
+			continue // Skip workshops that can't be loaded
+		}
+		loaded = append(loaded, w)
+	}
+
+	return loaded, nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", watchCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", watchCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", watchCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", watchCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", watchCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", watchCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", watchCmd)
+	cfg.BindFlagConfigToCommand("save.shouldCommit", watchCmd)
+	cfg.BindFlagConfigToCommand("save.commit.message", watchCmd)
+	cfg.BindFlagConfigToCommand("save.requireTests", watchCmd)
+	cfg.BindFlagConfigToCommand("save.index", watchCmd)
+	cfg.BindFlagConfigToCommand("watch.debounce", watchCmd)
+	cfg.BindFlagConfigToCommand("watch.sync", watchCmd)
+
+	watchCmd.Flags().BoolP("yes", "y", false, "With --sync, restore the saved solution of the new exercise without asking")
+
+	return watchCmd
+}
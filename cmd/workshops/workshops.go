@@ -0,0 +1,230 @@
+package workshops
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/output"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cfg *config.Config
+)
+
+const timeFormat = "2006-01-02 15:04"
+
+var (
+	flagPath      string
+	searchOptions workshop.SearchOptions
+	outputDir     string
+)
+
+type workshopsDocument struct {
+	Workshops []workshopInfo `json:"workshops" yaml:"workshops"`
+	Selected  *selection     `json:"selected,omitempty" yaml:"selected,omitempty"`
+}
+
+type workshopInfo struct {
+	output.WorkshopInfo `yaml:",inline"`
+	HasPlayground       bool                 `json:"hasPlayground" yaml:"hasPlayground"`
+	Exercise            *output.ExerciseInfo `json:"exercise,omitempty" yaml:"exercise,omitempty"`
+	LastActivity        *time.Time           `json:"lastActivity,omitempty" yaml:"lastActivity,omitempty"`
+	Saved               int                  `json:"saved" yaml:"saved"`
+	Total               int                  `json:"total" yaml:"total"`
+	// Error is why the playground exercise of the workshop could not be found, the rest of the info is still listed
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type selection struct {
+	Path   string `json:"path" yaml:"path"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+var workshopsCmd = &cobra.Command{
+	Use:   "workshops",
+	Short: "List the workshops kody can find",
+	Long:  `List every workshop found under the configured workshops directories, with their current playground exercise, last activity and saved exercises. Also tells which workshop is used by the other commands and why.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return output.WithCode(output.CodeInvalidConfig, checkAndSetupConfigs(cmd))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := output.FromConfig(cfg)
+
+		workshopPaths, err := workshop.FindWorkshops(searchOptions)
+		if err != nil {
+			return output.WithCode(output.CodeWorkshopNotFound, fmt.Errorf("finding workshops: %w", err))
+		}
+
+		doc := workshopsDocument{Workshops: []workshopInfo{}}
+		var latest *workshopInfo
+
+		for _, path := range workshopPaths {
+			w, err := workshop.WorkshopFromPath(path)
+			if err != nil {
+				out.Printf("Skipping '%s': %v\n", path, err)
+				continue
+			}
+
+			info, err := describeWorkshop(w)
+			if err != nil {
+				return err
+			}
+			doc.Workshops = append(doc.Workshops, *info)
+		}
+
+		for i := range doc.Workshops {
+			info := &doc.Workshops[i]
+			if info.LastActivity != nil && (latest == nil || info.LastActivity.After(*latest.LastActivity)) {
+				latest = info
+			}
+		}
+
+		resolution, err := workshop.Resolve(workshop.ResolveOptionsFromConfig(cfg, flagPath))
+		if err == nil {
+			doc.Selected = &selection{
+				Path:   resolution.Workshop.Path,
+				Reason: selectionReason(resolution, latest),
+			}
+		}
+
+		printWorkshops(out, doc)
+		return out.Document(doc)
+	},
+}
+
+func selectionReason(resolution *workshop.Resolution, latest *workshopInfo) string {
+	switch resolution.Source {
+	case workshop.FromFlag:
+		return "it was passed with the --workshop flag"
+	case workshop.FromWorkingDir:
+		return "kody is running from inside it"
+	case workshop.FromConfig:
+		return "it is set in the workshop.dir configuration, so auto-detection is not used"
+	}
+
+	if latest != nil {
+		return fmt.Sprintf("it has the most recently modified playground (%s)", latest.LastActivity.Format(timeFormat))
+	}
+	return "it has the most recently modified playground"
+}
+
+func describeWorkshop(w *workshop.Workshop) (*workshopInfo, error) {
+	info := &workshopInfo{
+		WorkshopInfo:  *output.NewWorkshopInfo(w),
+		HasPlayground: w.HasPlayground(),
+	}
+
+	if info.HasPlayground {
+		err := w.UseHashIndex(config.DefaultIndexDir(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("loading exercise hash index: %w", err)
+		}
+
+		exercise, err := w.PlaygroundExercise()
+		if err != nil && !errors.Is(err, workshop.ErrNoExactMatch) {
+			// A broken playground shouldn't hide the other workshops
+			info.Error = fmt.Sprintf("getting playground exercise: %v", err)
+		} else {
+			info.Exercise = output.NewExerciseInfo(exercise)
+		}
+
+		info.LastActivity, err = w.PlaygroundModTime()
+		if err != nil {
+			return nil, fmt.Errorf("getting last activity of '%s': %w", w.Path, err)
+		}
+	}
+
+	exercises, err := w.Exercises()
+	if err != nil {
+		return nil, fmt.Errorf("listing exercises of '%s': %w", w.Path, err)
+	}
+	info.Total = len(exercises)
+
+	for _, exercise := range exercises {
+		savedAt, err := workshop.SavedExerciseTime(outputDir, w, exercise)
+		if err != nil {
+			return nil, fmt.Errorf("checking saved solution for exercise %s: %w", exercise.BreadCrumbs(), err)
+		}
+		if savedAt != nil {
+			info.Saved++
+		}
+	}
+
+	return info, nil
+}
+
+func printWorkshops(out *output.Printer, doc workshopsDocument) {
+	if len(doc.Workshops) == 0 {
+		out.Printf("No workshops found in '%s'\n", searchOptions)
+		return
+	}
+
+	for _, info := range doc.Workshops {
+		marker := " "
+		if doc.Selected != nil && doc.Selected.Path == info.Path {
+			marker = ">"
+		}
+
+		out.Printf("%s %s (%s)\n", marker, info.Title, info.Slug)
+		out.Printf("    path:          %s\n", info.Path)
+		out.Printf("    slug source:   %s\n", info.SlugSource)
+		for _, warning := range info.Warnings {
+			out.Printf("    warning:       %s\n", warning)
+		}
+
+		if !info.HasPlayground {
+			out.Printf("    playground:    none\n")
+		} else if info.Exercise != nil {
+			out.Printf("    playground:    %s\n", info.Exercise.BreadCrumbs)
+		} else {
+			out.Printf("    playground:    unknown exercise\n")
+		}
+
+		if info.Error != "" {
+			out.Printf("    error:         %s\n", info.Error)
+		}
+
+		if info.LastActivity != nil {
+			out.Printf("    last activity: %s\n", info.LastActivity.Format(timeFormat))
+		}
+
+		out.Printf("    saved:         %d/%d exercises\n", info.Saved, info.Total)
+	}
+
+	if doc.Selected != nil {
+		out.Printf("\nUsing '%s' because %s.\n", doc.Selected.Path, doc.Selected.Reason)
+	}
+}
+
+func checkAndSetupConfigs(cmd *cobra.Command) error {
+	flagPath = ""
+	if workshopPathFlag := cmd.Flags().Lookup("workshop"); workshopPathFlag != nil && workshopPathFlag.Changed {
+		flagPath = workshopPathFlag.Value.String()
+	}
+	outputDir = cfg.GetString("save.output.directory")
+	searchOptions = workshop.SearchOptionsFromConfig(cfg)
+
+	if len(searchOptions.Roots) == 0 {
+		return errors.New("please provide the directories where the workshops are located using the --workshops or --roots flags, or the workshops.dir or workshops.roots configurations")
+	}
+
+	return nil
+}
+
+func GetCmd(configuration *config.Config) *cobra.Command {
+	cfg = configuration
+
+	cfg.BindFlagConfigToCommand("workshop.dir", workshopsCmd)
+	cfg.BindFlagConfigToCommand("workshops.dir", workshopsCmd)
+	cfg.BindFlagConfigToCommand("workshops.roots", workshopsCmd)
+	cfg.BindFlagConfigToCommand("workshops.depth", workshopsCmd)
+	cfg.BindFlagConfigToCommand("workshops.include", workshopsCmd)
+	cfg.BindFlagConfigToCommand("workshops.exclude", workshopsCmd)
+	cfg.BindFlagConfigToCommand("save.output.directory", workshopsCmd)
+
+	return workshopsCmd
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	gap "github.com/muesli/go-app-paths"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -57,16 +58,42 @@ func (c *Config) BindFlagConfigToCommand(key string, cmd *cobra.Command) {
 		case FlagConfig[bool]:
 			cmd.PersistentFlags().BoolP(v.FlagName, v.FlagShortHand, v.Default, v.Description)
 			c.viper.BindPFlag(key, cmd.PersistentFlags().Lookup(v.FlagName))
+		case FlagConfig[[]string]:
+			cmd.PersistentFlags().StringSliceP(v.FlagName, v.FlagShortHand, v.Default, v.Description)
+			c.viper.BindPFlag(key, cmd.PersistentFlags().Lookup(v.FlagName))
 		default:
 			panic(fmt.Sprintf("unsupported type: %T", fc))
 		}
 	}
 }
 
-func AddFlagConfig[T string | int | bool](c *Config, flagConfig FlagConfig[T]) {
+func AddFlagConfig[T string | int | bool | []string](c *Config, flagConfig FlagConfig[T]) {
 	c.configFlagMap[flagConfig.Key] = flagConfig
 }
 
+// BindCommandFlags binds the configuration keys to the flags of the command being executed.
+// The same key can be bound to flags of several commands, and only the flags of the command
+// being executed should take effect.
+func (c *Config) BindCommandFlags(cmd *cobra.Command) {
+	for key, fc := range c.configFlagMap {
+		var flagName string
+		switch v := fc.(type) {
+		case FlagConfig[string]:
+			flagName = v.FlagName
+		case FlagConfig[int]:
+			flagName = v.FlagName
+		case FlagConfig[bool]:
+			flagName = v.FlagName
+		case FlagConfig[[]string]:
+			flagName = v.FlagName
+		}
+
+		if flag := cmd.Flags().Lookup(flagName); flag != nil {
+			c.viper.BindPFlag(key, flag)
+		}
+	}
+}
+
 func (c *Config) Read() error {
 	paths, err := c.gapScope.LookupConfig(configName + "." + configType)
 	if err != nil {
@@ -159,6 +186,32 @@ func (c *Config) GetInt(key string) int {
 	return c.viper.GetInt(key)
 }
 
+func (c *Config) GetStringSlice(key string) []string {
+	return c.viper.GetStringSlice(key)
+}
+
+// GetFlatStringMap returns the map of strings under key. Viper splits keys on dots, so a key with dots
+// set with Set is stored as nested maps, e.g. "next.js" as next: {js: ...}. Nested maps are joined back
+// into their dotted keys.
+func (c *Config) GetFlatStringMap(key string) map[string]string {
+	flat := make(map[string]string)
+	flattenStringMap(flat, "", cast.ToStringMap(c.viper.Get(key)))
+	return flat
+}
+
+func flattenStringMap(flat map[string]string, prefix string, m map[string]interface{}) {
+	for key, value := range m {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenStringMap(flat, key, nested)
+			continue
+		}
+		flat[key] = cast.ToString(value)
+	}
+}
+
 func (c *Config) Set(key, value string) {
 	c.viper.Set(key, value)
 }
@@ -11,3 +11,30 @@ func DefaultSaveDir(cfg *Config) string {
 	return filepath.Join(dataDir, "save")
 
 }
+
+func DefaultIndexDir(cfg *Config) string {
+	dataDir, err := cfg.DataDir()
+	if err != nil {
+		dataDir = "."
+	}
+
+	return filepath.Join(dataDir, "index")
+}
+
+func DefaultPlaygroundStateDir(cfg *Config) string {
+	dataDir, err := cfg.DataDir()
+	if err != nil {
+		dataDir = "."
+	}
+
+	return filepath.Join(dataDir, "playgrounds")
+}
+
+func DefaultProgressDBPath(cfg *Config) string {
+	dataDir, err := cfg.DataDir()
+	if err != nil {
+		dataDir = "."
+	}
+
+	return filepath.Join(dataDir, "progress.db")
+}
@@ -0,0 +1,289 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// Line is a line of a diff between two texts, the old one and the new one.
+type Line struct {
+	Op   Op
+	Text string
+	// OldNumber and NewNumber are the 1-based numbers of the line in the old and new texts, 0 when the
+	// line is not part of that text.
+	OldNumber int
+	NewNumber int
+	// NoNewline tells that the line is the last one of its text, and that the text doesn't end with a
+	// line ending.
+	NoNewline bool
+}
+
+// SplitLines splits a text in lines, without their line endings.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Texts returns the shortest edit from the lines of the text a to the lines of the text b. Like in git, a
+// last line without line ending differs from the same line with one, so texts that only differ in their
+// final line ending have a change.
+func Texts(a string, b string) []Line {
+	// A line never has a line ending, so one marks the last line of a text without it
+	const noNewline = "\n"
+	markLast := func(text string) []string {
+		lines := SplitLines(text)
+		if len(lines) > 0 && !strings.HasSuffix(text, "\n") {
+			lines[len(lines)-1] += noNewline
+		}
+		return lines
+	}
+
+	lines := Lines(markLast(a), markLast(b))
+	for i := range lines {
+		if text, ok := strings.CutSuffix(lines[i].Text, noNewline); ok {
+			lines[i].Text = text
+			lines[i].NoNewline = true
+		}
+	}
+	return lines
+}
+
+// Lines returns the shortest edit from the lines of a to the lines of b, with the lines they have in common.
+func Lines(a []string, b []string) []Line {
+	// Most edits only touch a small part of a file, the common prefix and suffix are left out of the search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]Op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, Equal)
+	}
+	ops = append(ops, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, Equal)
+	}
+
+	lines := make([]Line, 0, len(ops))
+	var i, j int
+	for _, op := range ops {
+		switch op {
+		case Equal:
+			lines = append(lines, Line{Op: Equal, Text: a[i], OldNumber: i + 1, NewNumber: j + 1})
+			i++
+			j++
+		case Delete:
+			lines = append(lines, Line{Op: Delete, Text: a[i], OldNumber: i + 1})
+			i++
+		case Insert:
+			lines = append(lines, Line{Op: Insert, Text: b[j], NewNumber: j + 1})
+			j++
+		}
+	}
+
+	return lines
+}
+
+// myers finds the shortest edit script from a to b with the algorithm from Eugene W. Myers' paper
+// "An O(ND) Difference Algorithm and Its Variations".
+func myers(a []string, b []string) []Op {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	max := n + m
+	offset := max + 1
+	// v holds, for each diagonal k, the furthest x reached in a. trace keeps v after each step d, only
+	// for the diagonals -d..d that step can reach, to walk the path back at the end.
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	var d int
+search:
+	for d = 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				break search
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	ops := make([]Op, 0, n+m)
+	x, y := n, m
+	for ; d > 0; d-- {
+		previous := trace[d-1]
+		at := func(k int) int { return previous[k+d-1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, Equal)
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, Insert)
+		} else {
+			ops = append(ops, Delete)
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, Equal)
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+
+	return ops
+}
+
+// Hunk is a group of changes close to each other, with the unchanged lines around them.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// Header returns the header of the hunk in the unified format, e.g. "@@ -1,4 +1,5 @@".
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+func hunkRange(start int, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// Hunks groups the changes of a diff in hunks, with up to context unchanged lines around them. A negative
+// context is treated as 0.
+func Hunks(lines []Line, context int) []Hunk {
+	context = max(context, 0)
+	var hunks []Hunk
+
+	for i := 0; i < len(lines); {
+		if lines[i].Op == Equal {
+			i++
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := i
+		// Extend the hunk while the next change is close enough for their contexts to touch
+		for end < len(lines) {
+			if lines[end].Op != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].Op == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+
+		hunks = append(hunks, newHunk(lines, start, end))
+		i = end
+	}
+
+	return hunks
+}
+
+func newHunk(lines []Line, start int, end int) Hunk {
+	hunk := Hunk{Lines: lines[start:end]}
+
+	// The start of an empty range is the line before it
+	oldBefore, newBefore := 0, 0
+	for _, line := range lines[:start] {
+		if line.Op != Insert {
+			oldBefore++
+		}
+		if line.Op != Delete {
+			newBefore++
+		}
+	}
+
+	for _, line := range hunk.Lines {
+		if line.Op != Insert {
+			hunk.OldLines++
+		}
+		if line.Op != Delete {
+			hunk.NewLines++
+		}
+	}
+
+	hunk.OldStart = oldBefore + 1
+	if hunk.OldLines == 0 {
+		hunk.OldStart = oldBefore
+	}
+	hunk.NewStart = newBefore + 1
+	if hunk.NewLines == 0 {
+		hunk.NewStart = newBefore
+	}
+
+	return hunk
+}
+
+// Count returns the number of inserted and deleted lines of a diff.
+func Count(lines []Line) (inserted int, deleted int) {
+	for _, line := range lines {
+		switch line.Op {
+		case Insert:
+			inserted++
+		case Delete:
+			deleted++
+		}
+	}
+	return inserted, deleted
+}
@@ -0,0 +1,192 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// format writes the lines of a diff like the body of a unified diff, e.g. []string{" a", "-b", "+c"}.
+func format(lines []Line) []string {
+	formatted := make([]string, 0, len(lines))
+	for _, line := range lines {
+		switch line.Op {
+		case Insert:
+			formatted = append(formatted, "+"+line.Text)
+		case Delete:
+			formatted = append(formatted, "-"+line.Text)
+		default:
+			formatted = append(formatted, " "+line.Text)
+		}
+	}
+	return formatted
+}
+
+func TestTexts(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []string
+	}{
+		{name: "empty", a: "", b: "", want: []string{}},
+		{name: "equal", a: "a\nb\n", b: "a\nb\n", want: []string{" a", " b"}},
+		{name: "insert only", a: "", b: "a\nb\n", want: []string{"+a", "+b"}},
+		{name: "delete only", a: "a\nb\n", b: "", want: []string{"-a", "-b"}},
+		{name: "insert in the middle", a: "a\nc\n", b: "a\nb\nc\n", want: []string{" a", "+b", " c"}},
+		{name: "replace", a: "a\nb\nc\n", b: "a\nx\nc\n", want: []string{" a", "-b", "+x", " c"}},
+		{name: "crlf", a: "a\r\nb\r\n", b: "a\nb\n", want: []string{" a", " b"}},
+		{name: "newline added", a: "a\nb", b: "a\nb\n", want: []string{" a", "-b", "+b"}},
+		{name: "newline removed", a: "a\nb\n", b: "a\nb", want: []string{" a", "-b", "+b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := format(Texts(tt.a, tt.b)); !slices.Equal(got, tt.want) {
+				t.Errorf("Texts(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextsNoNewline(t *testing.T) {
+	lines := Texts("a\nb", "a\nb\n")
+	var noNewline []string
+	for _, line := range lines {
+		if line.NoNewline {
+			noNewline = append(noNewline, format([]Line{line})...)
+		}
+	}
+	if want := []string{"-b"}; !slices.Equal(noNewline, want) {
+		t.Errorf("lines without newline = %q, want %q", noNewline, want)
+	}
+}
+
+func TestLinesNumbers(t *testing.T) {
+	lines := Lines([]string{"a", "b", "c"}, []string{"a", "x", "c"})
+	want := []Line{
+		{Op: Equal, Text: "a", OldNumber: 1, NewNumber: 1},
+		{Op: Delete, Text: "b", OldNumber: 2},
+		{Op: Insert, Text: "x", NewNumber: 2},
+		{Op: Equal, Text: "c", OldNumber: 3, NewNumber: 3},
+	}
+	if !slices.Equal(lines, want) {
+		t.Errorf("Lines() = %+v, want %+v", lines, want)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	// Lines 1 to 12, with 3 changed and 11 deleted, 7 unchanged lines apart
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n12\n"
+
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    []string
+	}{
+		{name: "empty", a: "", b: "", context: 3, want: nil},
+		{name: "equal", a: old, b: old, context: 3, want: nil},
+		{name: "context 0", a: old, b: new, context: 0, want: []string{"@@ -3 +3 @@", "@@ -11 +10,0 @@"}},
+		{name: "context 3", a: old, b: new, context: 3, want: []string{"@@ -1,6 +1,6 @@", "@@ -8,5 +8,4 @@"}},
+		// The contexts of the changes touch, so they are in one hunk
+		{name: "context 4", a: old, b: new, context: 4, want: []string{"@@ -1,12 +1,11 @@"}},
+		{name: "negative context", a: old, b: new, context: -1, want: []string{"@@ -3 +3 @@", "@@ -11 +10,0 @@"}},
+		{name: "insert only", a: "", b: "a\nb\n", context: 3, want: []string{"@@ -0,0 +1,2 @@"}},
+		{name: "delete only", a: "a\nb\n", b: "", context: 3, want: []string{"@@ -1,2 +0,0 @@"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []string
+			for _, hunk := range Hunks(Texts(tt.a, tt.b), tt.context) {
+				headers = append(headers, hunk.Header())
+			}
+			if !slices.Equal(headers, tt.want) {
+				t.Errorf("Hunks() headers = %q, want %q", headers, tt.want)
+			}
+		})
+	}
+}
+
+func TestHunksLines(t *testing.T) {
+	hunks := Hunks(Texts("1\n2\n3\n4\n5\n", "1\n2\nthree\n4\n5\n"), 1)
+	if len(hunks) != 1 {
+		t.Fatalf("Hunks() returned %d hunks, want 1", len(hunks))
+	}
+	if got, want := format(hunks[0].Lines), []string{" 2", "-3", "+three", " 4"}; !slices.Equal(got, want) {
+		t.Errorf("hunk lines = %q, want %q", got, want)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	opts := FormatOptions{OldLabel: "saved", NewLabel: "playground", Context: 3}
+
+	tests := []struct {
+		name     string
+		fileDiff *FileDiff
+		want     string
+	}{
+		{
+			name:     "modified",
+			fileDiff: &FileDiff{Path: "src/app.js", Status: Modified, Lines: Texts("a\nb\nc\n", "a\nx\nc\n")},
+			want:     "--- saved/src/app.js\n+++ playground/src/app.js\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name:     "added",
+			fileDiff: &FileDiff{Path: "new.js", Status: Added, Lines: Texts("", "a\n")},
+			want:     "--- /dev/null\n+++ playground/new.js\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:     "deleted",
+			fileDiff: &FileDiff{Path: "old.js", Status: Deleted, Lines: Texts("a\n", "")},
+			want:     "--- saved/old.js\n+++ /dev/null\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:     "newline added",
+			fileDiff: &FileDiff{Path: "app.js", Status: Modified, Lines: Texts("a\nb", "a\nb\n")},
+			want:     "--- saved/app.js\n+++ playground/app.js\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:     "no newline in both",
+			fileDiff: &FileDiff{Path: "app.js", Status: Modified, Lines: Texts("a\nb", "x\nb")},
+			want:     "--- saved/app.js\n+++ playground/app.js\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n\\ No newline at end of file\n",
+		},
+		{
+			name:     "binary",
+			fileDiff: &FileDiff{Path: "logo.png", Status: Modified, Binary: true},
+			want:     "Binary files saved/logo.png and playground/logo.png differ\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.fileDiff, opts); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilesNewlineOnly(t *testing.T) {
+	dir := t.TempDir()
+	old, new := filepath.Join(dir, "old.js"), filepath.Join(dir, "new.js")
+	if err := os.WriteFile(old, []byte("a\nb"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(new, []byte("a\nb\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fileDiff, err := Files(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fileDiff == nil {
+		t.Fatal("Files() = nil, want a diff")
+	}
+	if fileDiff.Inserted != 1 || fileDiff.Deleted != 1 {
+		t.Errorf("Files() counted %d insertions and %d deletions, want 1 and 1", fileDiff.Inserted, fileDiff.Deleted)
+	}
+}
@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type Status string
+
+const (
+	Added    Status = "added"
+	Deleted  Status = "deleted"
+	Modified Status = "modified"
+)
+
+// FileDiff is the difference between the two versions of a file in two directories.
+type FileDiff struct {
+	// Path is the path of the file relative to the directories, with forward slashes.
+	Path   string
+	Status Status
+	// Binary files are only compared as a whole, they have no lines.
+	Binary   bool
+	Lines    []Line
+	Inserted int
+	Deleted  int
+}
+
+// Dirs compares the regular files of the directories old and new, leaving out the directories for which
+// skipDir returns true. Only the files that differ are returned, sorted by path.
+func Dirs(old string, new string, skipDir func(name string) bool) ([]*FileDiff, error) {
+	oldFiles, err := listFiles(old, skipDir)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := listFiles(new, skipDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool)
+	for _, path := range append(oldFiles, newFiles...) {
+		paths[path] = true
+	}
+
+	var diffs []*FileDiff
+	for path := range paths {
+		fileDiff, err := Files(filepath.Join(old, filepath.FromSlash(path)), filepath.Join(new, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, err
+		}
+		if fileDiff != nil {
+			fileDiff.Path = path
+			diffs = append(diffs, fileDiff)
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+
+	return diffs, nil
+}
+
+// listFiles lists the files of dir, or nothing if dir doesn't exist.
+func listFiles(dir string, skipDir func(name string) bool) ([]string, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return directory.Files(dir, skipDir)
+}
+
+// Files compares the files at the paths old and new, where a missing file counts as an empty one.
+// Returns nil when they have the same contents.
+func Files(old string, new string) (*FileDiff, error) {
+	oldData, oldExists, err := readFile(old)
+	if err != nil {
+		return nil, err
+	}
+	newData, newExists, err := readFile(new)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldExists == newExists && bytes.Equal(oldData, newData) {
+		return nil, nil
+	}
+
+	fileDiff := &FileDiff{Status: Modified}
+	switch {
+	case !oldExists:
+		fileDiff.Status = Added
+	case !newExists:
+		fileDiff.Status = Deleted
+	}
+
+	if IsBinary(oldData) || IsBinary(newData) {
+		fileDiff.Binary = true
+		return fileDiff, nil
+	}
+
+	fileDiff.Lines = Texts(string(oldData), string(newData))
+	fileDiff.Inserted, fileDiff.Deleted = Count(fileDiff.Lines)
+
+	return fileDiff, nil
+}
+
+func readFile(path string) ([]byte, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading '%s': %w", path, err)
+	}
+	return data, true, nil
+}
+
+// IsBinary tells if data looks like the contents of a binary file, as git does: by looking for a NUL byte
+// in its first 8000 bytes.
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), 8000)], 0) != -1
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"slices"
+)
+
+// MergeLabels name the versions of a text in the conflict markers of a merge.
+type MergeLabels struct {
+	Ours   string
+	Theirs string
+}
+
+// Merge3 merges the changes made to base in ours and in theirs, line by line. Where both changed the same
+// lines in different ways, the merged text has both versions between conflict markers, ours first.
+// Returns the merged lines and the number of conflicts.
+func Merge3(base []string, ours []string, theirs []string, labels MergeLabels) ([]string, int) {
+	oursMatch := matches(base, ours)
+	theirsMatch := matches(base, theirs)
+
+	var merged []string
+	var conflicts int
+
+	b, o, t := 0, 0, 0
+	for {
+		// Lines kept by both sides are taken as they are
+		for b < len(base) && oursMatch[b] == o && theirsMatch[b] == t {
+			merged = append(merged, base[b])
+			b++
+			o++
+			t++
+		}
+		if b == len(base) && o == len(ours) && t == len(theirs) {
+			break
+		}
+
+		// The chunk where the sides differ ends at the next base line both sides kept
+		next := b
+		for next < len(base) && (oursMatch[next] < 0 || theirsMatch[next] < 0) {
+			next++
+		}
+		oursEnd, theirsEnd := len(ours), len(theirs)
+		if next < len(base) {
+			oursEnd, theirsEnd = oursMatch[next], theirsMatch[next]
+		}
+
+		baseChunk, oursChunk, theirsChunk := base[b:next], ours[o:oursEnd], theirs[t:theirsEnd]
+		switch {
+		case slices.Equal(oursChunk, baseChunk), slices.Equal(oursChunk, theirsChunk):
+			merged = append(merged, theirsChunk...)
+		case slices.Equal(theirsChunk, baseChunk):
+			merged = append(merged, oursChunk...)
+		default:
+			conflicts++
+			merged = append(merged, "<<<<<<< "+labels.Ours)
+			merged = append(merged, oursChunk...)
+			merged = append(merged, "=======")
+			merged = append(merged, theirsChunk...)
+			merged = append(merged, ">>>>>>> "+labels.Theirs)
+		}
+
+		b, o, t = next, oursEnd, theirsEnd
+	}
+
+	return merged, conflicts
+}
+
+// matches maps each line of base to the line of other it was kept as, or -1 if it was changed.
+func matches(base []string, other []string) []int {
+	match := make([]int, len(base))
+	for i := range match {
+		match[i] = -1
+	}
+	for _, line := range Lines(base, other) {
+		if line.Op == Equal {
+			match[line.OldNumber-1] = line.NewNumber - 1
+		}
+	}
+	return match
+}
@@ -0,0 +1,103 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	labels := MergeLabels{Ours: "playground", Theirs: "saved"}
+
+	tests := []struct {
+		name               string
+		base, ours, theirs []string
+		want               []string
+		conflicts          int
+	}{
+		{name: "empty", want: nil},
+		{
+			name:   "unchanged",
+			base:   []string{"a", "b"},
+			ours:   []string{"a", "b"},
+			theirs: []string{"a", "b"},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "only ours changed",
+			base:   []string{"a", "b", "c"},
+			ours:   []string{"a", "x", "c"},
+			theirs: []string{"a", "b", "c"},
+			want:   []string{"a", "x", "c"},
+		},
+		{
+			name:   "only theirs changed",
+			base:   []string{"a", "b", "c"},
+			ours:   []string{"a", "b", "c"},
+			theirs: []string{"a", "b", "c", "d"},
+			want:   []string{"a", "b", "c", "d"},
+		},
+		{
+			name:   "different lines changed",
+			base:   []string{"a", "b", "c", "d", "e"},
+			ours:   []string{"x", "b", "c", "d", "e"},
+			theirs: []string{"a", "b", "c", "d", "y"},
+			want:   []string{"x", "b", "c", "d", "y"},
+		},
+		{
+			name:   "same change in both",
+			base:   []string{"a", "b", "c"},
+			ours:   []string{"a", "x", "c"},
+			theirs: []string{"a", "x", "c"},
+			want:   []string{"a", "x", "c"},
+		},
+		{
+			name:   "deleted in ours",
+			base:   []string{"a", "b", "c", "d"},
+			ours:   []string{"a", "d"},
+			theirs: []string{"a", "b", "c", "d", "e"},
+			want:   []string{"a", "d", "e"},
+		},
+		{
+			name:      "same line changed",
+			base:      []string{"a", "b", "c"},
+			ours:      []string{"a", "x", "c"},
+			theirs:    []string{"a", "y", "c"},
+			want:      []string{"a", "<<<<<<< playground", "x", "=======", "y", ">>>>>>> saved", "c"},
+			conflicts: 1,
+		},
+		{
+			name:      "two conflicts",
+			base:      []string{"a", "b", "c", "d", "e"},
+			ours:      []string{"x", "b", "c", "d", "z"},
+			theirs:    []string{"y", "b", "c", "d", "w"},
+			want:      []string{"<<<<<<< playground", "x", "=======", "y", ">>>>>>> saved", "b", "c", "d", "<<<<<<< playground", "z", "=======", "w", ">>>>>>> saved"},
+			conflicts: 2,
+		},
+		{
+			// Without a base, everything was added on both sides
+			name:      "no base",
+			ours:      []string{"a"},
+			theirs:    []string{"b"},
+			want:      []string{"<<<<<<< playground", "a", "=======", "b", ">>>>>>> saved"},
+			conflicts: 1,
+		},
+		{
+			name:   "no base and same lines",
+			ours:   []string{"a", "b"},
+			theirs: []string{"a", "b"},
+			want:   []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3(tt.base, tt.ours, tt.theirs, labels)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Merge3() = %q, want %q", got, tt.want)
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("Merge3() conflicts = %d, want %d", conflicts, tt.conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// NoNewlineMarker follows the last line of a text that doesn't end with a line ending.
+const NoNewlineMarker = `\ No newline at end of file`
+
+// FormatOptions control how diffs are written.
+type FormatOptions struct {
+	// OldLabel and NewLabel prefix the paths of the files in the headers, e.g. "saved" and "playground".
+	OldLabel string
+	NewLabel string
+	// Context is the number of unchanged lines shown around the changes.
+	Context int
+	// Color highlights the diff with ANSI escape codes.
+	Color bool
+}
+
+func (o FormatOptions) paint(color string, s string) string {
+	if !o.Color {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// Unified returns the diff of a file in the unified format.
+func Unified(fileDiff *FileDiff, opts FormatOptions) string {
+	var b strings.Builder
+
+	oldName := opts.OldLabel + "/" + fileDiff.Path
+	newName := opts.NewLabel + "/" + fileDiff.Path
+	switch fileDiff.Status {
+	case Added:
+		oldName = "/dev/null"
+	case Deleted:
+		newName = "/dev/null"
+	}
+
+	if fileDiff.Binary {
+		fmt.Fprintf(&b, "Binary files %s and %s differ\n", oldName, newName)
+		return b.String()
+	}
+
+	b.WriteString(opts.paint(colorBold, "--- "+oldName) + "\n")
+	b.WriteString(opts.paint(colorBold, "+++ "+newName) + "\n")
+
+	for _, hunk := range Hunks(fileDiff.Lines, opts.Context) {
+		b.WriteString(opts.paint(colorCyan, hunk.Header()) + "\n")
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case Insert:
+				b.WriteString(opts.paint(colorGreen, "+"+line.Text) + "\n")
+			case Delete:
+				b.WriteString(opts.paint(colorRed, "-"+line.Text) + "\n")
+			default:
+				b.WriteString(" " + line.Text + "\n")
+			}
+			if line.NoNewline {
+				b.WriteString(NoNewlineMarker + "\n")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// statBarWidth is the widest the bar of changes of a file gets in WriteStat.
+const statBarWidth = 40
+
+// WriteStat writes a summary of the changes of each file, like git diff --stat does.
+func WriteStat(w io.Writer, diffs []*FileDiff, opts FormatOptions) error {
+	var pathWidth, mostChanges, inserted, deleted int
+	for _, fileDiff := range diffs {
+		pathWidth = max(pathWidth, len(fileDiff.Path))
+		mostChanges = max(mostChanges, fileDiff.Inserted+fileDiff.Deleted)
+		inserted += fileDiff.Inserted
+		deleted += fileDiff.Deleted
+	}
+	countWidth := len(fmt.Sprint(mostChanges))
+
+	for _, fileDiff := range diffs {
+		if fileDiff.Binary {
+			if _, err := fmt.Fprintf(w, " %-*s | %*s\n", pathWidth, fileDiff.Path, countWidth, "Bin"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		plus, minus := fileDiff.Inserted, fileDiff.Deleted
+		if mostChanges > statBarWidth {
+			// Scale the bar, but keep at least one sign for files with changes of that kind
+			plus = scale(plus, mostChanges)
+			minus = scale(minus, mostChanges)
+		}
+		bar := opts.paint(colorGreen, strings.Repeat("+", plus)) + opts.paint(colorRed, strings.Repeat("-", minus))
+
+		_, err := fmt.Fprintf(w, " %-*s | %*d %s\n", pathWidth, fileDiff.Path, countWidth, fileDiff.Inserted+fileDiff.Deleted, bar)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, " %d %s changed, %d %s(+), %d %s(-)\n",
+		len(diffs), plural(len(diffs), "file", "files"),
+		inserted, plural(inserted, "insertion", "insertions"),
+		deleted, plural(deleted, "deletion", "deletions"))
+	return err
+}
+
+func scale(changes int, mostChanges int) int {
+	if changes == 0 {
+		return 0
+	}
+	return max(changes*statBarWidth/mostChanges, 1)
+}
+
+func plural(n int, singular string, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
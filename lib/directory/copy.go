@@ -7,12 +7,21 @@ import (
 	"path/filepath"
 )
 
-func CopyFS(dir string, fsys fs.FS) error {
+// CopyFS copies the files and directories of fsys to dir. Entries for which skip returns true are left
+// out, with all their contents for directories. A nil skip copies everything.
+func CopyFS(dir string, fsys fs.FS, skip func(path string, d fs.DirEntry) bool) error {
 	return fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
+		if skip != nil && path != "." && skip(path, d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
 		fpath, err := filepath.Localize(path)
 		if err != nil {
 			return err
@@ -1,8 +1,13 @@
 package directory
 
 import (
+	"bytes"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
 func Exists(path string) bool {
@@ -21,3 +26,127 @@ func IsGitRepo(path string) bool {
 
 	return true
 }
+
+// SameContents reports whether the directories a and b have the same regular files with the same
+// contents, leaving out the directories for which skipDir returns true.
+func SameContents(a string, b string, skipDir func(name string) bool) (bool, error) {
+	filesA, err := regularFiles(a, skipDir)
+	if err != nil {
+		return false, err
+	}
+	filesB, err := regularFiles(b, skipDir)
+	if err != nil {
+		return false, err
+	}
+
+	if len(filesA) != len(filesB) {
+		return false, nil
+	}
+
+	for path, sizeA := range filesA {
+		sizeB, ok := filesB[path]
+		if !ok || sizeA != sizeB {
+			return false, nil
+		}
+	}
+
+	for path := range filesA {
+		dataA, err := os.ReadFile(filepath.Join(a, path))
+		if err != nil {
+			return false, err
+		}
+		dataB, err := os.ReadFile(filepath.Join(b, path))
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(dataA, dataB) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Files returns the paths of the regular files in dir, relative to dir and with forward slashes, leaving out
+// the directories for which skipDir returns true.
+func Files(dir string, skipDir func(name string) bool) ([]string, error) {
+	files, err := regularFiles(dir, skipDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// LatestModTime returns the most recent modification time of dir and the files and directories in it,
+// leaving out the directories for which skipDir returns true.
+func LatestModTime(dir string, skipDir func(name string) bool) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && path != dir && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting modification time of '%s': %w", dir, err)
+	}
+
+	return latest, nil
+}
+
+// regularFiles returns the sizes of the regular files in dir, by their path relative to dir.
+func regularFiles(dir string, skipDir func(name string) bool) (map[string]int64, error) {
+	files := make(map[string]int64)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != dir && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing files of '%s': %w", dir, err)
+	}
+
+	return files, nil
+}
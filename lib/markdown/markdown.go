@@ -0,0 +1,41 @@
+package markdown
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Link returns a link to the target path from the directory base, with each segment of the path escaped
+// for URLs, e.g. "../react-fundamentals/01.02%20nested". When there is no relative path from base to
+// target, e.g. when base is empty, the link is a file URL with the absolute path of target.
+func Link(base string, target string) string {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		absTarget = target
+	}
+	fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(absTarget)}).String()
+
+	if base == "" {
+		return fileURL
+	}
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return fileURL
+	}
+	rel, err := filepath.Rel(absBase, absTarget)
+	if err != nil {
+		return fileURL
+	}
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// TableCell escapes the text so it can be put in a cell of a table.
+func TableCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
@@ -0,0 +1,37 @@
+package markdown
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	root := t.TempDir()
+
+	tests := []struct {
+		name   string
+		base   string
+		target string
+		want   string
+	}{
+		{name: "child", base: root, target: filepath.Join(root, "react-fundamentals", "README.md"), want: "react-fundamentals/README.md"},
+		{name: "sibling", base: filepath.Join(root, "react-hooks"), target: filepath.Join(root, "README.md"), want: "../README.md"},
+		{name: "escaped", base: root, target: filepath.Join(root, "my solutions", "01#1?.md"), want: "my%20solutions/01%231%3F.md"},
+		{name: "same", base: root, target: root, want: "."},
+		{name: "no base", base: "", target: filepath.Join(root, "a b"), want: "file://" + filepath.ToSlash(root) + "/a%20b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Link(tt.base, tt.target); got != tt.want {
+				t.Errorf("Link(%q, %q) = %q, want %q", tt.base, tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableCell(t *testing.T) {
+	if got, want := TableCell("a | b\nc"), `a \| b c`; got != want {
+		t.Errorf("TableCell() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package output
+
+import (
+	"github.com/andrerfcsantos/kody/lib/config"
+	"os"
+)
+
+// FromConfig returns a printer to the standard output using the format in the output.format configuration.
+// On an invalid format, the returned printer uses the text format.
+func FromConfig(cfg *config.Config) (*Printer, error) {
+	format, err := ParseFormat(cfg.GetString("output.format"))
+	return New(format, os.Stdout), err
+}
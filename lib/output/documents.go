@@ -0,0 +1,52 @@
+package output
+
+import "github.com/andrerfcsantos/kody/lib/workshop"
+
+// WorkshopInfo is the representation of a workshop shared by the structured outputs of the commands.
+type WorkshopInfo struct {
+	Title      string   `json:"title" yaml:"title"`
+	Slug       string   `json:"slug" yaml:"slug"`
+	SlugSource string   `json:"slugSource" yaml:"slugSource"`
+	Path       string   `json:"path" yaml:"path"`
+	Warnings   []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+func NewWorkshopInfo(w *workshop.Workshop) *WorkshopInfo {
+	if w == nil {
+		return nil
+	}
+	slug, source := w.SlugWithSource()
+	return &WorkshopInfo{
+		Title:      w.Title(),
+		Slug:       slug,
+		SlugSource: string(source),
+		Path:       w.Path,
+		Warnings:   w.Warnings(),
+	}
+}
+
+// ExerciseInfo is the representation of an exercise shared by the structured outputs of the commands.
+type ExerciseInfo struct {
+	SectionNumber int    `json:"sectionNumber" yaml:"sectionNumber"`
+	SectionSlug   string `json:"sectionSlug" yaml:"sectionSlug"`
+	Number        int    `json:"number" yaml:"number"`
+	Slug          string `json:"slug" yaml:"slug"`
+	State         string `json:"state" yaml:"state"`
+	BreadCrumbs   string `json:"breadcrumbs" yaml:"breadcrumbs"`
+	Path          string `json:"path" yaml:"path"`
+}
+
+func NewExerciseInfo(e *workshop.Exercise) *ExerciseInfo {
+	if e == nil {
+		return nil
+	}
+	return &ExerciseInfo{
+		SectionNumber: e.Section.Number,
+		SectionSlug:   e.Section.Slug,
+		Number:        e.Number,
+		Slug:          e.Slug,
+		State:         string(e.State),
+		BreadCrumbs:   e.BreadCrumbs(),
+		Path:          e.Path(),
+	}
+}
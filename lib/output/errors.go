@@ -0,0 +1,76 @@
+package output
+
+import "errors"
+
+// Code identifies the kind of an error in the structured outputs, so scripts don't need to parse messages.
+type Code string
+
+const (
+	CodeUnknown              Code = "unknown"
+	CodeInvalidConfig        Code = "invalid_config"
+	CodeWorkshopNotFound     Code = "workshop_not_found"
+	CodeExerciseNotDetected  Code = "exercise_not_detected"
+	CodeSavedExerciseMissing Code = "saved_exercise_not_found"
+	CodeConfirmationRequired Code = "confirmation_required"
+	CodeCopyFailed           Code = "copy_failed"
+	CodeCommitFailed         Code = "commit_failed"
+	CodeTestsNotFound        Code = "tests_not_found"
+	CodeTestsFailed          Code = "tests_failed"
+	CodeMergeConflicts       Code = "merge_conflicts"
+)
+
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// WithCode attaches an error code to err. Returns nil if err is nil.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// CodeOf returns the outermost code attached to err, or CodeUnknown if there is none.
+func CodeOf(err error) Code {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return CodeUnknown
+}
+
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reportedError) Unwrap() error {
+	return e.err
+}
+
+// Reported marks err as already reported in the document of the command, e.g. the failed tests, so it is
+// not printed again as an error document. Returns nil if err is nil.
+func Reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &reportedError{err: err}
+}
+
+func isReported(err error) bool {
+	var reported *reportedError
+	return errors.As(err, &reported)
+}
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", Text:
+		return Text, nil
+	case JSON, YAML:
+		return Format(s), nil
+	}
+	return Text, fmt.Errorf("unknown output format '%s', must be one of: json, yaml, text", s)
+}
+
+// Printer is the output layer shared by all the commands. Free-form messages are only printed in text mode,
+// while documents are only printed in the structured modes (json and yaml).
+type Printer struct {
+	format Format
+	w      io.Writer
+}
+
+func New(format Format, w io.Writer) *Printer {
+	return &Printer{format: format, w: w}
+}
+
+func (p *Printer) Format() Format {
+	return p.format
+}
+
+func (p *Printer) IsText() bool {
+	return p.format == Text
+}
+
+func (p *Printer) Printf(format string, args ...any) {
+	if p.IsText() {
+		fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+func (p *Printer) Print(args ...any) {
+	if p.IsText() {
+		fmt.Fprint(p.w, args...)
+	}
+}
+
+func (p *Printer) Println(args ...any) {
+	if p.IsText() {
+		fmt.Fprintln(p.w, args...)
+	}
+}
+
+// Document prints a structured document in json or yaml. It does nothing in text mode.
+func (p *Printer) Document(doc any) error {
+	switch p.format {
+	case JSON:
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("encoding json output: %w", err)
+		}
+	case YAML:
+		encoder := yaml.NewEncoder(p.w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("encoding yaml output: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("encoding yaml output: %w", err)
+		}
+	}
+	return nil
+}
+
+type errorDocument struct {
+	Error ErrorInfo `json:"error" yaml:"error"`
+}
+
+type ErrorInfo struct {
+	Code    Code   `json:"code" yaml:"code"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// NewErrorInfo returns the code and message of err, to report it in the document of a command.
+func NewErrorInfo(err error) *ErrorInfo {
+	return &ErrorInfo{Code: CodeOf(err), Message: err.Error()}
+}
+
+// Error prints an error as a document in the structured modes, or as a plain message to errWriter in text mode.
+// Errors already reported in the document of the command are only printed in text mode.
+func (p *Printer) Error(err error, errWriter io.Writer) {
+	if p.IsText() {
+		fmt.Fprintln(errWriter, err)
+		return
+	}
+	if isReported(err) {
+		return
+	}
+
+	docErr := p.Document(errorDocument{Error: *NewErrorInfo(err)})
+	if docErr != nil {
+		fmt.Fprintln(errWriter, err)
+	}
+}
@@ -0,0 +1,334 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/testrun"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Kind is the kind of activity recorded in an event.
+type Kind string
+
+const (
+	// Detected is recorded every time kody detects the exercise in the playground.
+	Detected Kind = "detected"
+	Saved    Kind = "saved"
+	Restored Kind = "restored"
+	Tested   Kind = "tested"
+	// Edited is the last time a file in the playground was modified, as seen when the exercise was detected.
+	Edited Kind = "edited"
+)
+
+// Event is a piece of activity on an exercise.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Kind     Kind      `json:"kind"`
+	Workshop string    `json:"workshop"`
+	// Exercise identifies the exercise by its section and exercise numbers, e.g. "01.02".
+	Exercise    string `json:"exercise"`
+	BreadCrumbs string `json:"breadcrumbs"`
+	// Path is the destination of a save or the source of a restore.
+	Path string `json:"path,omitempty"`
+	// Passed, Summary and Counts are set for test runs.
+	Passed  *bool           `json:"passed,omitempty"`
+	Summary string          `json:"summary,omitempty"`
+	Counts  *testrun.Counts `json:"counts,omitempty"`
+}
+
+// ExerciseKey returns the key of the exercise in the database, made of its section and exercise numbers,
+// so the history of an exercise survives renames and is shared by its problem and its solution.
+func ExerciseKey(exercise *workshop.Exercise) string {
+	return fmt.Sprintf("%02d.%02d", exercise.Section.Number, exercise.Number)
+}
+
+func NewEvent(kind Kind, w *workshop.Workshop, exercise *workshop.Exercise) Event {
+	return Event{
+		Time:        time.Now(),
+		Kind:        kind,
+		Workshop:    w.Slug(),
+		Exercise:    ExerciseKey(exercise),
+		BreadCrumbs: exercise.BreadCrumbs(),
+	}
+}
+
+// DetectionEvents returns the events of detecting the exercise in the playground of the workshop: the
+// detection itself and the last time the playground was edited.
+func DetectionEvents(w *workshop.Workshop, exercise *workshop.Exercise) []Event {
+	events := []Event{NewEvent(Detected, w, exercise)}
+
+	if modTime, err := w.PlaygroundModTime(); err == nil && !modTime.IsZero() {
+		edited := NewEvent(Edited, w, exercise)
+		edited.Time = *modTime
+		events = append(events, edited)
+	}
+
+	return events
+}
+
+// NewTestEvent returns the event of a test run of the exercise.
+func NewTestEvent(w *workshop.Workshop, exercise *workshop.Exercise, result *testrun.Result) Event {
+	event := NewEvent(Tested, w, exercise)
+	event.Time = result.StartedAt
+	event.Passed = &result.Passed
+	event.Summary = result.Summary()
+	event.Counts = result.Counts
+	return event
+}
+
+// SyncEvents returns the events of a sync of the playground of the workshop.
+func SyncEvents(w *workshop.Workshop, result *solutions.SyncResult) []Event {
+	if result.Exercise == nil {
+		return nil
+	}
+	events := DetectionEvents(w, result.Exercise)
+
+	if result.Saved != nil && result.Saved.Copied {
+		saved := NewEvent(Saved, w, result.PreviousExercise)
+		saved.Path = result.Saved.Destination
+		events = append(events, saved)
+	}
+
+	if result.Restored {
+		restored := NewEvent(Restored, w, result.Exercise)
+		restored.Path = result.SavedSolution
+		events = append(events, restored)
+	}
+
+	return events
+}
+
+// The events are kept in a bucket per workshop, with a nested bucket per exercise.
+// Keys are the time of the event followed by a sequence number, so they sort chronologically.
+var eventsBucket = []byte("events")
+
+// openTimeout is how long to wait for another kody process to release the database.
+const openTimeout = 5 * time.Second
+
+type DB struct {
+	bolt *bolt.DB
+}
+
+// Open opens the database at path, creating it if needed. The database can only be open by one
+// process at a time, so it should be closed as soon as possible.
+func Open(path string) (*DB, error) {
+	return OpenWithTimeout(path, openTimeout)
+}
+
+// OpenWithTimeout opens the database at path like Open, waiting at most timeout for another kody process
+// to release it.
+func OpenWithTimeout(path string, timeout time.Duration) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("creating progress database dir: %w", err)
+	}
+
+	boltDB, err := bolt.Open(path, 0640, &bolt.Options{Timeout: timeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("progress database '%s' is in use by another kody process", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening progress database '%s': %w", path, err)
+	}
+
+	return &DB{bolt: boltDB}, nil
+}
+
+func (db *DB) Close() error {
+	return db.bolt.Close()
+}
+
+// Record stores the events.
+func (db *DB) Record(events ...Event) error {
+	return db.bolt.Update(func(tx *bolt.Tx) error {
+		root, err := tx.CreateBucketIfNotExists(eventsBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			workshopBucket, err := root.CreateBucketIfNotExists([]byte(event.Workshop))
+			if err != nil {
+				return fmt.Errorf("creating bucket for workshop '%s': %w", event.Workshop, err)
+			}
+			exerciseBucket, err := workshopBucket.CreateBucketIfNotExists([]byte(event.Exercise))
+			if err != nil {
+				return fmt.Errorf("creating bucket for exercise '%s': %w", event.Exercise, err)
+			}
+
+			key := make([]byte, 16)
+			binary.BigEndian.PutUint64(key, uint64(event.Time.UnixNano()))
+
+			// The same edit is seen by every command that runs until the next one, it is only stored once
+			if event.Kind == Edited && hasEvent(exerciseBucket, key[:8], Edited) {
+				continue
+			}
+
+			seq, err := exerciseBucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			binary.BigEndian.PutUint64(key[8:], seq)
+
+			value, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("encoding event: %w", err)
+			}
+			if err := exerciseBucket.Put(key, value); err != nil {
+				return fmt.Errorf("storing event: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// hasEvent reports whether the bucket has an event of the kind at the time in timeKey.
+func hasEvent(bucket *bolt.Bucket, timeKey []byte, kind Kind) bool {
+	c := bucket.Cursor()
+	for k, v := c.Seek(timeKey); k != nil && bytes.HasPrefix(k, timeKey); k, v = c.Next() {
+		var event Event
+		if err := json.Unmarshal(v, &event); err == nil && event.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Query selects events. Empty fields match everything.
+type Query struct {
+	Workshop string
+	Exercise string
+	Kinds    []Kind
+	Since    time.Time
+}
+
+func (q Query) matches(event Event) bool {
+	if !q.Since.IsZero() && event.Time.Before(q.Since) {
+		return false
+	}
+	if len(q.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range q.Kinds {
+		if event.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Events returns the events matching the query, oldest first.
+func (db *DB) Events(q Query) ([]Event, error) {
+	var events []Event
+
+	err := db.bolt.View(func(tx *bolt.Tx) error {
+		root := tx.Bucket(eventsBucket)
+		if root == nil {
+			return nil
+		}
+
+		return root.ForEachBucket(func(workshopName []byte) error {
+			if q.Workshop != "" && q.Workshop != string(workshopName) {
+				return nil
+			}
+
+			workshopBucket := root.Bucket(workshopName)
+			return workshopBucket.ForEachBucket(func(exerciseName []byte) error {
+				if q.Exercise != "" && q.Exercise != string(exerciseName) {
+					return nil
+				}
+
+				return workshopBucket.Bucket(exerciseName).ForEach(func(k, v []byte) error {
+					var event Event
+					if err := json.Unmarshal(v, &event); err != nil {
+						return fmt.Errorf("decoding event: %w", err)
+					}
+					if q.matches(event) {
+						events = append(events, event)
+					}
+					return nil
+				})
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading progress database: %w", err)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events, nil
+}
+
+// Latest returns the most recent event of each exercise of the workshop matching the kinds, by exercise key.
+func (db *DB) Latest(workshopSlug string, kinds ...Kind) (map[string]Event, error) {
+	events, err := db.Events(Query{Workshop: workshopSlug, Kinds: kinds})
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]Event)
+	for _, event := range events {
+		latest[event.Exercise] = event
+	}
+
+	return latest, nil
+}
+
+// CheckTestsPassed returns an error unless the last test run of the exercise recorded in the database at
+// path passed, and the playground wasn't edited after it.
+func CheckTestsPassed(path string, w *workshop.Workshop, exercise *workshop.Exercise) error {
+	db, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	events, err := db.Events(Query{Workshop: w.Slug(), Exercise: ExerciseKey(exercise), Kinds: []Kind{Tested, Edited}})
+	if err != nil {
+		return err
+	}
+
+	var lastTest, lastEdit *Event
+	for i := range events {
+		if events[i].Kind == Tested {
+			lastTest = &events[i]
+		} else {
+			lastEdit = &events[i]
+		}
+	}
+
+	switch {
+	case lastTest == nil:
+		return errors.New("its tests were never run, run kody test before switching exercises")
+	case lastTest.Passed == nil || !*lastTest.Passed:
+		return fmt.Errorf("its last test run failed (%s)", lastTest.Summary)
+	case lastEdit != nil && lastEdit.Time.After(lastTest.Time):
+		return errors.New("it was edited after its tests last ran, run kody test before switching exercises")
+	}
+
+	return nil
+}
+
+// Record opens the database at path, stores the events and closes it again.
+func Record(path string, events ...Event) error {
+	db, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Record(events...)
+}
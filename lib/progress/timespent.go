@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultIdleThreshold is the longest gap between two events that still counts as active time.
+const DefaultIdleThreshold = 15 * time.Minute
+
+// Interval is a stretch of active time on an exercise.
+type Interval struct {
+	Workshop    string
+	Exercise    string
+	BreadCrumbs string
+	Start       time.Time
+	Duration    time.Duration
+}
+
+// ActiveIntervals estimates the time spent on each exercise from the events, which must be sorted by time.
+// The time between two consecutive events is attributed to the exercise of the first one, unless it is
+// longer than idleThreshold, in which case it is considered idle time and left out. Events of all the
+// workshops should be passed together, so time is not counted twice when switching between workshops.
+func ActiveIntervals(events []Event, idleThreshold time.Duration) []Interval {
+	var intervals []Interval
+
+	for i := 0; i+1 < len(events); i++ {
+		current, next := events[i], events[i+1]
+
+		gap := next.Time.Sub(current.Time)
+		if gap <= 0 || gap > idleThreshold {
+			continue
+		}
+
+		intervals = append(intervals, Interval{
+			Workshop:    current.Workshop,
+			Exercise:    current.Exercise,
+			BreadCrumbs: current.BreadCrumbs,
+			Start:       current.Time,
+			Duration:    gap,
+		})
+	}
+
+	return intervals
+}
+
+// WeekStart returns the start of the week of t, on Monday at midnight in the location of t.
+func WeekStart(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.AddDate(0, 0, -daysSinceMonday).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+type WeekTotal struct {
+	Start time.Time     `json:"start" yaml:"start"`
+	Total time.Duration `json:"total" yaml:"total"`
+}
+
+type ExerciseTime struct {
+	Exercise    string        `json:"exercise" yaml:"exercise"`
+	BreadCrumbs string        `json:"breadcrumbs" yaml:"breadcrumbs"`
+	Total       time.Duration `json:"total" yaml:"total"`
+}
+
+type SectionTime struct {
+	Section   string          `json:"section" yaml:"section"`
+	Name      string          `json:"name" yaml:"name"`
+	Total     time.Duration   `json:"total" yaml:"total"`
+	Exercises []*ExerciseTime `json:"exercises" yaml:"exercises"`
+}
+
+type WorkshopTime struct {
+	Workshop string         `json:"workshop" yaml:"workshop"`
+	Total    time.Duration  `json:"total" yaml:"total"`
+	Weeks    []WeekTotal    `json:"weeks" yaml:"weeks"`
+	Sections []*SectionTime `json:"sections" yaml:"sections"`
+}
+
+// TimeReport is the time spent on the exercises, by workshop, section and exercise, with the totals of
+// the last weeks.
+type TimeReport struct {
+	Total     time.Duration   `json:"total" yaml:"total"`
+	Weeks     []WeekTotal     `json:"weeks" yaml:"weeks"`
+	Workshops []*WorkshopTime `json:"workshops" yaml:"workshops"`
+}
+
+// Workshop returns the time spent on a workshop, or nil if no time was spent on it.
+func (r *TimeReport) Workshop(slug string) *WorkshopTime {
+	for _, w := range r.Workshops {
+		if w.Workshop == slug {
+			return w
+		}
+	}
+	return nil
+}
+
+// Exercise returns the time spent on an exercise of a workshop, by exercise key.
+func (r *TimeReport) Exercise(slug string, exercise string) time.Duration {
+	w := r.Workshop(slug)
+	if w == nil {
+		return 0
+	}
+	for _, section := range w.Sections {
+		for _, e := range section.Exercises {
+			if e.Exercise == exercise {
+				return e.Total
+			}
+		}
+	}
+	return 0
+}
+
+// NewTimeReport groups the intervals by workshop, section and exercise. The weekly totals cover the
+// number of weeks up to the week of now, the current week last, and are left out when weeks is 0 or less.
+func NewTimeReport(intervals []Interval, weeks int, now time.Time) *TimeReport {
+	report := &TimeReport{Weeks: emptyWeeks(weeks, now)}
+	workshops := make(map[string]*WorkshopTime)
+	sections := make(map[string]*SectionTime)
+	exercises := make(map[string]*ExerciseTime)
+
+	for _, interval := range intervals {
+		w, ok := workshops[interval.Workshop]
+		if !ok {
+			w = &WorkshopTime{Workshop: interval.Workshop, Weeks: emptyWeeks(weeks, now)}
+			workshops[interval.Workshop] = w
+			report.Workshops = append(report.Workshops, w)
+		}
+
+		sectionKey, _, _ := strings.Cut(interval.Exercise, ".")
+		section, ok := sections[interval.Workshop+"/"+sectionKey]
+		if !ok {
+			name, _, _ := strings.Cut(interval.BreadCrumbs, " > ")
+			section = &SectionTime{Section: sectionKey, Name: name}
+			sections[interval.Workshop+"/"+sectionKey] = section
+			w.Sections = append(w.Sections, section)
+		}
+
+		exercise, ok := exercises[interval.Workshop+"/"+interval.Exercise]
+		if !ok {
+			exercise = &ExerciseTime{Exercise: interval.Exercise, BreadCrumbs: interval.BreadCrumbs}
+			exercises[interval.Workshop+"/"+interval.Exercise] = exercise
+			section.Exercises = append(section.Exercises, exercise)
+		}
+
+		report.Total += interval.Duration
+		w.Total += interval.Duration
+		section.Total += interval.Duration
+		exercise.Total += interval.Duration
+		addToWeek(report.Weeks, interval)
+		addToWeek(w.Weeks, interval)
+	}
+
+	sort.Slice(report.Workshops, func(i, j int) bool {
+		return report.Workshops[i].Workshop < report.Workshops[j].Workshop
+	})
+	for _, w := range report.Workshops {
+		sort.Slice(w.Sections, func(i, j int) bool {
+			return w.Sections[i].Section < w.Sections[j].Section
+		})
+		for _, section := range w.Sections {
+			sort.Slice(section.Exercises, func(i, j int) bool {
+				return section.Exercises[i].Exercise < section.Exercises[j].Exercise
+			})
+		}
+	}
+
+	return report
+}
+
+func emptyWeeks(weeks int, now time.Time) []WeekTotal {
+	totals := make([]WeekTotal, max(weeks, 0))
+	current := WeekStart(now)
+	for i := range totals {
+		totals[i].Start = current.AddDate(0, 0, -7*(weeks-1-i))
+	}
+	return totals
+}
+
+func addToWeek(weeks []WeekTotal, interval Interval) {
+	start := WeekStart(interval.Start.In(time.Local))
+	for i := range weeks {
+		if weeks[i].Start.Equal(start) {
+			weeks[i].Total += interval.Duration
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/markdown"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+type Format string
+
+const (
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "markdown", "md":
+		return Markdown, nil
+	case "html":
+		return HTML, nil
+	}
+	return "", fmt.Errorf("unknown report format '%s', must be one of: markdown, html", s)
+}
+
+const dateFormat = "2006-01-02 15:04"
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = map[string]any{
+	"date":        formatDate,
+	"duration":    FormatDuration,
+	"cell":        markdown.TableCell,
+	"statusIcon":  statusIcon,
+	"statusClass": statusClass,
+}
+
+var (
+	markdownTemplate = template.Must(template.New("report.md.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.md.tmpl"))
+	htmlTemplate     = htmltemplate.Must(htmltemplate.New("report.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/report.html.tmpl"))
+)
+
+// Render writes the report to w in the format. HTML reports are standalone pages, with no external
+// resources.
+func Render(w io.Writer, format Format, report *Report) error {
+	var err error
+	switch format {
+	case Markdown:
+		err = markdownTemplate.Execute(w, report)
+	case HTML:
+		err = htmlTemplate.Execute(w, report)
+	default:
+		err = fmt.Errorf("unknown report format '%s'", format)
+	}
+	if err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	return nil
+}
+
+// FormatDuration formats a duration in hours and minutes, e.g. "1h 05m".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%dh %02dm", d/time.Hour, (d%time.Hour)/time.Minute)
+}
+
+func formatDate(t any) string {
+	switch t := t.(type) {
+	case time.Time:
+		return t.Local().Format(dateFormat)
+	case *time.Time:
+		if t != nil {
+			return t.Local().Format(dateFormat)
+		}
+	}
+	return ""
+}
+
+func statusIcon(status Status) string {
+	switch status {
+	case Completed:
+		return "✅"
+	case InProgress:
+		return "🚧"
+	}
+	return "⬜"
+}
+
+func statusClass(status Status) string {
+	return strings.ReplaceAll(string(status), " ", "-")
+}
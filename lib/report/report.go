@@ -0,0 +1,200 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/markdown"
+	"github.com/andrerfcsantos/kody/lib/progress"
+	"github.com/andrerfcsantos/kody/lib/solutions"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"time"
+)
+
+// Status is how far along an exercise is.
+type Status string
+
+const (
+	NotStarted Status = "not started"
+	// InProgress exercises have some recorded activity but no saved solution yet.
+	InProgress Status = "in progress"
+	Completed  Status = "completed"
+)
+
+// Report is the progress on one or more workshops.
+type Report struct {
+	GeneratedAt time.Time     `json:"generatedAt" yaml:"generatedAt"`
+	TimeSpent   time.Duration `json:"timeSpent" yaml:"timeSpent"`
+	Workshops   []*Workshop   `json:"workshops" yaml:"workshops"`
+}
+
+type Workshop struct {
+	Title     string        `json:"title" yaml:"title"`
+	Slug      string        `json:"slug" yaml:"slug"`
+	Path      string        `json:"path" yaml:"path"`
+	Completed int           `json:"completed" yaml:"completed"`
+	Total     int           `json:"total" yaml:"total"`
+	TimeSpent time.Duration `json:"timeSpent" yaml:"timeSpent"`
+	Sections  []*Section    `json:"sections" yaml:"sections"`
+}
+
+type Section struct {
+	Number    int           `json:"number" yaml:"number"`
+	Slug      string        `json:"slug" yaml:"slug"`
+	Title     string        `json:"title" yaml:"title"`
+	Completed int           `json:"completed" yaml:"completed"`
+	Total     int           `json:"total" yaml:"total"`
+	TimeSpent time.Duration `json:"timeSpent" yaml:"timeSpent"`
+	Exercises []*Exercise   `json:"exercises" yaml:"exercises"`
+}
+
+type Exercise struct {
+	Number      int        `json:"number" yaml:"number"`
+	Slug        string     `json:"slug" yaml:"slug"`
+	Title       string     `json:"title" yaml:"title"`
+	BreadCrumbs string     `json:"breadcrumbs" yaml:"breadcrumbs"`
+	Status      Status     `json:"status" yaml:"status"`
+	SavedAt     *time.Time `json:"savedAt,omitempty" yaml:"savedAt,omitempty"`
+	SavedPath   string     `json:"savedPath,omitempty" yaml:"savedPath,omitempty"`
+	// Link is the saved solution relative to the location of the report, as a URL path.
+	Link      string        `json:"link,omitempty" yaml:"link,omitempty"`
+	TimeSpent time.Duration `json:"timeSpent" yaml:"timeSpent"`
+	Tests     *Tests        `json:"tests,omitempty" yaml:"tests,omitempty"`
+}
+
+// Tests is the last test run of an exercise.
+type Tests struct {
+	Passed  bool      `json:"passed" yaml:"passed"`
+	Summary string    `json:"summary" yaml:"summary"`
+	RanAt   time.Time `json:"ranAt" yaml:"ranAt"`
+}
+
+// Percent returns the percentage of completed exercises of the workshop, rounded down.
+func (w *Workshop) Percent() int {
+	return percent(w.Completed, w.Total)
+}
+
+func (s *Section) Percent() int {
+	return percent(s.Completed, s.Total)
+}
+
+func percent(completed int, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return completed * 100 / total
+}
+
+type Options struct {
+	// OutputDir is the directory with the saved solutions.
+	OutputDir string
+	// LinkBase is the directory the links to the saved solutions are relative to, usually where the
+	// report is written.
+	LinkBase string
+	// TimeSpent is the time spent on the exercises. No time is reported when nil.
+	TimeSpent *progress.TimeReport
+	// TestRuns are the latest test runs of the exercises, by workshop slug and exercise key.
+	TestRuns map[string]map[string]progress.Event
+	Now      time.Time
+}
+
+// Build builds the report on the progress of the workshops.
+func Build(workshops []*workshop.Workshop, opts Options) (*Report, error) {
+	report := &Report{GeneratedAt: opts.Now, Workshops: []*Workshop{}}
+
+	for _, w := range workshops {
+		workshopReport, err := buildWorkshop(w, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Workshops = append(report.Workshops, workshopReport)
+		report.TimeSpent += workshopReport.TimeSpent
+	}
+
+	return report, nil
+}
+
+func buildWorkshop(w *workshop.Workshop, opts Options) (*Workshop, error) {
+	outline, err := w.Outline()
+	if err != nil {
+		return nil, fmt.Errorf("loading outline of '%s': %w", w.Path, err)
+	}
+
+	report := &Workshop{Title: w.Title(), Slug: w.Slug(), Path: w.Path, Sections: []*Section{}}
+
+	for _, outlineSection := range outline.Sections {
+		section := &Section{
+			Number:    outlineSection.Number,
+			Slug:      outlineSection.Slug,
+			Title:     outlineSection.Title(),
+			Exercises: []*Exercise{},
+		}
+
+		for _, outlineExercise := range outlineSection.Exercises {
+			exercise, err := buildExercise(w, outlineExercise.Exercise, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			section.Exercises = append(section.Exercises, exercise)
+			section.Total++
+			section.TimeSpent += exercise.TimeSpent
+			if exercise.Status == Completed {
+				section.Completed++
+			}
+		}
+
+		report.Sections = append(report.Sections, section)
+		report.Completed += section.Completed
+		report.Total += section.Total
+		report.TimeSpent += section.TimeSpent
+	}
+
+	return report, nil
+}
+
+func buildExercise(w *workshop.Workshop, e *workshop.Exercise, opts Options) (*Exercise, error) {
+	key := progress.ExerciseKey(e)
+
+	exercise := &Exercise{
+		Number:      e.Number,
+		Slug:        e.Slug,
+		Title:       e.Title(),
+		BreadCrumbs: e.BreadCrumbs(),
+		Status:      NotStarted,
+	}
+
+	if opts.TimeSpent != nil {
+		exercise.TimeSpent = opts.TimeSpent.Exercise(w.Slug(), key)
+	}
+
+	if event, ok := opts.TestRuns[w.Slug()][key]; ok && event.Passed != nil {
+		exercise.Tests = &Tests{Passed: *event.Passed, Summary: event.Summary, RanAt: event.Time}
+	}
+
+	if exercise.TimeSpent > 0 || exercise.Tests != nil {
+		exercise.Status = InProgress
+	}
+
+	savedPath, err := solutions.FindSaved(opts.OutputDir, w, e.Section.Number, e.Number)
+	if errors.Is(err, solutions.ErrNotSaved) {
+		return exercise, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("finding saved solution of exercise %s: %w", e.BreadCrumbs(), err)
+	}
+
+	info, err := os.Stat(savedPath)
+	if err != nil {
+		return nil, fmt.Errorf("getting info for saved exercise: %w", err)
+	}
+	savedAt := info.ModTime()
+
+	exercise.Status = Completed
+	exercise.SavedAt = &savedAt
+	exercise.SavedPath = savedPath
+	// Saved solutions are folders
+	exercise.Link = markdown.Link(opts.LinkBase, savedPath) + "/"
+
+	return exercise, nil
+}
@@ -0,0 +1,74 @@
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+)
+
+// codeStyle is the style of the highlighted code. The code is highlighted with CSS classes, the style is
+// written to assets/highlight.css.
+const codeStyle = "github"
+
+var codeFormatter = chromahtml.New(chromahtml.WithClasses(true))
+
+// instructions renders Markdown like GitHub does. Raw HTML, and so the JSX of the MDX files, is left out
+// of the output, and so are links with dangerous URLs like javascript:, so the instructions of a workshop
+// can't run scripts in the site.
+var instructions = goldmark.New(goldmark.WithExtensions(
+	extension.GFM,
+	highlighting.NewHighlighting(highlighting.WithStyle(codeStyle), highlighting.WithFormatOptions(chromahtml.WithClasses(true))),
+))
+
+var (
+	frontMatter = regexp.MustCompile(`\A---\n(?s:.*?)\n---\n`)
+	epicVideo   = regexp.MustCompile(`<EpicVideo\s+url="([^"]+)"[^>]*/>`)
+)
+
+// renderInstructions renders the README.mdx of an exercise as HTML.
+func renderInstructions(readme string) (template.HTML, error) {
+	source := frontMatter.ReplaceAllString(strings.ReplaceAll(readme, "\r\n", "\n"), "")
+	source = epicVideo.ReplaceAllString(source, "[▶ Watch the video]($1)")
+
+	var buf bytes.Buffer
+	if err := instructions.Convert([]byte(source), &buf); err != nil {
+		return "", fmt.Errorf("rendering instructions: %w", err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// highlight renders the contents of a file as HTML, with the syntax highlighted for the language of its
+// name. Files of unknown languages are shown as plain text.
+func highlight(name string, content string) (template.HTML, error) {
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, content)
+	if err != nil {
+		return "", fmt.Errorf("highlighting '%s': %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := codeFormatter.Format(&buf, styles.Get(codeStyle), tokens); err != nil {
+		return "", fmt.Errorf("highlighting '%s': %w", name, err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// writeHighlightCSS writes the CSS of the classes of the highlighted code.
+func writeHighlightCSS(w io.Writer) error {
+	return codeFormatter.WriteCSS(w, styles.Get(codeStyle))
+}
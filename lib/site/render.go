@@ -0,0 +1,260 @@
+package site
+
+import (
+	"embed"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/diff"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"html/template"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//go:embed templates assets
+var files embed.FS
+
+const dateFormat = "2006-01-02 15:04"
+
+// diffContext is the number of unchanged lines shown around the changes in the diffs.
+const diffContext = 3
+
+var funcs = template.FuncMap{
+	"href": href,
+	"date": func(t time.Time) string {
+		return t.Local().Format(dateFormat)
+	},
+}
+
+var layout = template.Must(template.New("layout.html").Funcs(funcs).ParseFS(files, "templates/layout.html"))
+
+var (
+	indexPage    = pageTemplate("templates/index.html")
+	workshopPage = pageTemplate("templates/workshop.html")
+	exercisePage = pageTemplate("templates/exercise.html")
+)
+
+func pageTemplate(name string) *template.Template {
+	return template.Must(template.Must(layout.Clone()).ParseFS(files, name))
+}
+
+// href returns the link to a page of the site, given by its path from the root of the site, from a page
+// whose path to the root is root.
+func href(root string, page string) string {
+	segments := strings.Split(page, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return root + strings.Join(segments, "/")
+}
+
+// page is the data available to the templates of the pages.
+type page struct {
+	// Root is the relative path from the page to the root of the site, e.g. "../".
+	Root        string
+	Title       string
+	Current     string
+	Workshops   []*siteWorkshop
+	GeneratedAt time.Time
+
+	Workshop *siteWorkshop
+	Section  *siteSection
+	Exercise *siteExercise
+	Previous *siteExercise
+	Next     *siteExercise
+	// Instructions is the rendered README.mdx of the exercise.
+	Instructions template.HTML
+	Files        []sourceFile
+	// Compared tells if the saved solution was compared to the official one, Diffs are the differences.
+	Compared bool
+	Diffs    []fileDiffView
+}
+
+type fileDiffView struct {
+	Path     string
+	Status   diff.Status
+	Binary   bool
+	Inserted int
+	Deleted  int
+	Lines    []diffLineView
+}
+
+type diffLineView struct {
+	Class string
+	Text  string
+}
+
+type renderer struct {
+	dest      string
+	workshops []*siteWorkshop
+	now       time.Time
+	pages     int
+}
+
+func (r *renderer) render() error {
+	if err := r.writeAssets(); err != nil {
+		return err
+	}
+
+	err := r.writePage("index.html", indexPage, &page{Title: "Solutions"})
+	if err != nil {
+		return err
+	}
+
+	for _, w := range r.workshops {
+		err := r.writePage(path.Join(w.Slug, "index.html"), workshopPage, &page{Title: w.Title, Workshop: w})
+		if err != nil {
+			return err
+		}
+
+		exercises := w.exercises()
+		for i, e := range exercises {
+			p := &page{
+				Title:    fmt.Sprintf("%s - %s", e.Title, w.Title),
+				Workshop: w,
+				Exercise: e,
+			}
+			if i > 0 {
+				p.Previous = exercises[i-1]
+			}
+			if i+1 < len(exercises) {
+				p.Next = exercises[i+1]
+			}
+
+			if e.Readme != "" {
+				p.Instructions, err = renderInstructions(e.Readme)
+				if err != nil {
+					return fmt.Errorf("exercise %s: %w", e.BreadCrumbs, err)
+				}
+			}
+
+			p.Files, err = savedFiles(e.SavedPath)
+			if err != nil {
+				return fmt.Errorf("reading saved files of exercise %s: %w", e.BreadCrumbs, err)
+			}
+
+			if e.SolutionPath != "" {
+				p.Compared = true
+				p.Diffs, err = diffWithSolution(e)
+				if err != nil {
+					return fmt.Errorf("comparing exercise %s with the official solution: %w", e.BreadCrumbs, err)
+				}
+			}
+
+			if err := r.writePage(e.Page, exercisePage, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *siteWorkshop) exercises() []*siteExercise {
+	var exercises []*siteExercise
+	for _, section := range w.Sections {
+		exercises = append(exercises, section.Exercises...)
+	}
+	return exercises
+}
+
+func (r *renderer) writeAssets() error {
+	assets, err := fs.Sub(files, "assets")
+	if err != nil {
+		return err
+	}
+	if err := os.CopyFS(filepath.Join(r.dest, "assets"), assets); err != nil {
+		return fmt.Errorf("copying site assets: %w", err)
+	}
+
+	css, err := os.Create(filepath.Join(r.dest, "assets", "highlight.css"))
+	if err != nil {
+		return fmt.Errorf("writing highlight.css: %w", err)
+	}
+	defer css.Close()
+	if err := writeHighlightCSS(css); err != nil {
+		return fmt.Errorf("writing highlight.css: %w", err)
+	}
+	if err := css.Close(); err != nil {
+		return fmt.Errorf("writing highlight.css: %w", err)
+	}
+
+	// Without it, GitHub Pages processes the site with Jekyll, which skips some files
+	if err := os.WriteFile(filepath.Join(r.dest, ".nojekyll"), nil, 0644); err != nil {
+		return fmt.Errorf("writing .nojekyll: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(r.dest, markerFile), []byte("This site is built by kody site build, its contents are replaced on every build.\n"), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", markerFile, err)
+	}
+
+	return nil
+}
+
+func (r *renderer) writePage(pagePath string, tmpl *template.Template, p *page) error {
+	p.Current = pagePath
+	p.Root = strings.Repeat("../", strings.Count(pagePath, "/"))
+	p.Workshops = r.workshops
+	p.GeneratedAt = r.now
+
+	destPath := filepath.Join(r.dest, filepath.FromSlash(pagePath))
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return fmt.Errorf("creating page directory: %w", err)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return fmt.Errorf("creating page: %w", err)
+	}
+	defer f.Close()
+
+	if err := tmpl.ExecuteTemplate(f, "layout.html", p); err != nil {
+		return fmt.Errorf("rendering page '%s': %w", pagePath, err)
+	}
+	r.pages++
+
+	return f.Close()
+}
+
+func diffWithSolution(e *siteExercise) ([]fileDiffView, error) {
+	diffs, err := diff.Dirs(e.SolutionPath, e.SavedPath, workshop.IsDependencyOrCacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]fileDiffView, 0, len(diffs))
+	for _, fileDiff := range diffs {
+		view := fileDiffView{
+			Path:     fileDiff.Path,
+			Status:   fileDiff.Status,
+			Binary:   fileDiff.Binary,
+			Inserted: fileDiff.Inserted,
+			Deleted:  fileDiff.Deleted,
+		}
+
+		for _, hunk := range diff.Hunks(fileDiff.Lines, diffContext) {
+			view.Lines = append(view.Lines, diffLineView{Class: "hunk", Text: hunk.Header()})
+			for _, line := range hunk.Lines {
+				switch line.Op {
+				case diff.Insert:
+					view.Lines = append(view.Lines, diffLineView{Class: "insert", Text: "+" + line.Text})
+				case diff.Delete:
+					view.Lines = append(view.Lines, diffLineView{Class: "delete", Text: "-" + line.Text})
+				default:
+					view.Lines = append(view.Lines, diffLineView{Class: "equal", Text: " " + line.Text})
+				}
+				if line.NoNewline {
+					view.Lines = append(view.Lines, diffLineView{Class: "hunk", Text: diff.NoNewlineMarker})
+				}
+			}
+		}
+
+		views = append(views, view)
+	}
+
+	return views, nil
+}
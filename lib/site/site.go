@@ -0,0 +1,309 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/diff"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// markerFile is written at the root of the sites built by kody. Directories without it are never
+// removed when building a site.
+const markerFile = ".kody-site"
+
+// maxFileSize is the size above which the contents of a saved file are not shown.
+const maxFileSize = 512 * 1024
+
+type Options struct {
+	// OutputDir is the directory with the saved solutions.
+	OutputDir string
+	// Dest is the directory the site is built in. Its previous contents are replaced.
+	Dest string
+	// Workshops are the workshops found on this machine. The instructions and official solutions of
+	// the exercises are taken from them, by workshop slug.
+	Workshops []*workshop.Workshop
+	Now       time.Time
+}
+
+type Result struct {
+	Dest      string   `json:"destination" yaml:"destination"`
+	Workshops int      `json:"workshops" yaml:"workshops"`
+	Exercises int      `json:"exercises" yaml:"exercises"`
+	Pages     int      `json:"pages" yaml:"pages"`
+	Warnings  []string `json:"warnings" yaml:"warnings"`
+}
+
+type siteWorkshop struct {
+	Slug     string
+	Title    string
+	Sections []*siteSection
+	// workshop is the workshop on this machine with the same slug, nil if there is none.
+	workshop *workshop.Workshop
+	outline  *workshop.Outline
+}
+
+type siteSection struct {
+	Number    int
+	Title     string
+	Folder    string
+	Exercises []*siteExercise
+}
+
+type siteExercise struct {
+	Number      int
+	Title       string
+	BreadCrumbs string
+	Folder      string
+	SavedPath   string
+	SavedAt     time.Time
+	// Readme is the README.mdx of the problem of the exercise, empty if the workshop is not on this machine.
+	Readme       string
+	SolutionPath string
+	// Page is the path of the page of the exercise, relative to the root of the site.
+	Page string
+}
+
+// Build renders the saved solutions of opts.OutputDir as a static website in opts.Dest.
+func Build(opts Options) (*Result, error) {
+	result := &Result{Dest: opts.Dest, Warnings: []string{}}
+
+	if err := prepareDest(opts.Dest); err != nil {
+		return result, err
+	}
+
+	workshops, err := loadSavedWorkshops(opts, result)
+	if err != nil {
+		return result, err
+	}
+
+	r := &renderer{dest: opts.Dest, workshops: workshops, now: opts.Now}
+	if err := r.render(); err != nil {
+		return result, err
+	}
+
+	result.Workshops = len(workshops)
+	for _, w := range workshops {
+		for _, section := range w.Sections {
+			result.Exercises += len(section.Exercises)
+		}
+	}
+	result.Pages = r.pages
+
+	return result, nil
+}
+
+// prepareDest empties the destination directory, as long as it is empty or holds a site built by kody.
+func prepareDest(dest string) error {
+	entries, err := os.ReadDir(dest)
+	if errors.Is(err, os.ErrNotExist) {
+		return os.MkdirAll(dest, 0755)
+	}
+	if err != nil {
+		return fmt.Errorf("reading site directory: %w", err)
+	}
+
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(dest, markerFile)); err != nil {
+			return fmt.Errorf("'%s' is not empty and was not built by kody, choose another directory for the site", dest)
+		}
+	}
+
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dest, entry.Name())); err != nil {
+			return fmt.Errorf("removing previous site: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func loadSavedWorkshops(opts Options, result *Result) ([]*siteWorkshop, error) {
+	entries, err := os.ReadDir(opts.OutputDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading output directory: %w", err)
+	}
+
+	localWorkshops := make(map[string]*workshop.Workshop)
+	for _, w := range opts.Workshops {
+		localWorkshops[w.Slug()] = w
+	}
+
+	absDest, err := filepath.Abs(opts.Dest)
+	if err != nil {
+		return nil, fmt.Errorf("getting absolute path of '%s': %w", opts.Dest, err)
+	}
+
+	var workshops []*siteWorkshop
+	for _, entry := range entries {
+		dir := filepath.Join(opts.OutputDir, entry.Name())
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if absDir, err := filepath.Abs(dir); err == nil && absDir == absDest {
+			continue
+		}
+
+		w := &siteWorkshop{Slug: entry.Name(), Title: entry.Name(), workshop: localWorkshops[entry.Name()]}
+		if w.workshop != nil {
+			w.Title = w.workshop.Title()
+			w.outline, err = w.workshop.Outline()
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("could not load the exercises of workshop '%s': %v", w.workshop.Path, err))
+			}
+		} else {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("workshop '%s' was not found on this machine, its exercises won't have instructions or the official solutions", entry.Name()))
+		}
+
+		if err := loadSavedSections(w, dir); err != nil {
+			return nil, err
+		}
+		if len(w.Sections) > 0 {
+			workshops = append(workshops, w)
+		}
+	}
+
+	return workshops, nil
+}
+
+func loadSavedSections(w *siteWorkshop, dir string) error {
+	sectionDirs, err := numberedDirs(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, sectionDir := range sectionDirs {
+		sectionNumber, sectionSlug := splitFolderName(sectionDir)
+		section := &siteSection{Number: sectionNumber, Title: sectionSlug, Folder: sectionDir}
+
+		var outlineSection *workshop.OutlineSection
+		if w.outline != nil {
+			outlineSection = w.outline.Section(strconv.Itoa(sectionNumber))
+		}
+		if outlineSection != nil {
+			section.Title = outlineSection.Title()
+		}
+
+		exerciseDirs, err := numberedDirs(filepath.Join(dir, sectionDir))
+		if err != nil {
+			return err
+		}
+
+		for _, exerciseDir := range exerciseDirs {
+			exerciseNumber, exerciseSlug := splitFolderName(exerciseDir)
+			savedPath := filepath.Join(dir, sectionDir, exerciseDir)
+
+			info, err := os.Stat(savedPath)
+			if err != nil {
+				return fmt.Errorf("getting info for saved exercise: %w", err)
+			}
+
+			exercise := &siteExercise{
+				Number:      exerciseNumber,
+				Title:       exerciseSlug,
+				BreadCrumbs: fmt.Sprintf("[%02d] %s > [%02d] %s", sectionNumber, sectionSlug, exerciseNumber, exerciseSlug),
+				Folder:      exerciseDir,
+				SavedPath:   savedPath,
+				SavedAt:     info.ModTime(),
+				Page:        strings.Join([]string{w.Slug, sectionDir, exerciseDir, "index.html"}, "/"),
+			}
+
+			if outlineSection != nil {
+				if outlineExercise := w.outline.Exercise(strconv.Itoa(sectionNumber), strconv.Itoa(exerciseNumber)); outlineExercise != nil {
+					exercise.Title = outlineExercise.Title()
+					exercise.BreadCrumbs = outlineExercise.BreadCrumbs()
+					exercise.SolutionPath = outlineExercise.SolutionPath
+					if outlineExercise.ProblemPath != "" {
+						if readme, err := os.ReadFile(filepath.Join(outlineExercise.ProblemPath, "README.mdx")); err == nil {
+							exercise.Readme = string(readme)
+						}
+					}
+				}
+			}
+
+			section.Exercises = append(section.Exercises, exercise)
+		}
+
+		if len(section.Exercises) > 0 {
+			w.Sections = append(w.Sections, section)
+		}
+	}
+
+	return nil
+}
+
+// numberedDirs returns the names of the directories in dir that start with a number, like "01.intro".
+func numberedDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading '%s': %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if number, _ := splitFolderName(entry.Name()); entry.IsDir() && number > 0 {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// splitFolderName splits a folder name like "01.intro" in its number and slug. The number is 0 when the
+// name doesn't start with one.
+func splitFolderName(name string) (int, string) {
+	numberPart, slug, found := strings.Cut(name, ".")
+	number, err := strconv.Atoi(numberPart)
+	if !found || err != nil {
+		return 0, name
+	}
+	return number, slug
+}
+
+type sourceFile struct {
+	Path string
+	// Code is the highlighted contents of the file.
+	Code template.HTML
+	// Omitted explains why the contents of the file are not shown, empty when they are.
+	Omitted string
+}
+
+func savedFiles(savedPath string) ([]sourceFile, error) {
+	paths, err := directory.Files(savedPath, workshop.IsDependencyOrCacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []sourceFile
+	for _, path := range paths {
+		file := sourceFile{Path: path}
+
+		data, err := os.ReadFile(filepath.Join(savedPath, filepath.FromSlash(path)))
+		if err != nil {
+			return nil, fmt.Errorf("reading saved file: %w", err)
+		}
+		switch {
+		case len(data) > maxFileSize:
+			file.Omitted = "File too big to show"
+		case diff.IsBinary(data):
+			file.Omitted = "Binary file"
+		default:
+			file.Code, err = highlight(path, string(data))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,280 @@
+package solutions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/markdown"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// indexMarker starts the first line of the index files written by kody. Files without it were written by
+// someone else and are never overwritten.
+const indexMarker = "<!-- Generated by kody"
+
+const indexDateFormat = "2006-01-02 15:04"
+
+// noteExcerptLength is the maximum length of the excerpt of the notes of an exercise shown in the index.
+const noteExcerptLength = 100
+
+// indexMeta is kept in the marker of the index of a workshop, so the index of the output directory can
+// show the progress of workshops that are not being saved.
+type indexMeta struct {
+	Title string `json:"title"`
+	Total int    `json:"total"`
+}
+
+// IndexResult lists the index files written by WriteIndexes.
+type IndexResult struct {
+	Written []string
+	// Warnings are the reasons some index files were not written.
+	Warnings []string
+}
+
+// WriteIndexes regenerates the index file named fileName of the saved solutions of the workshop, in its
+// folder of outputDir, and the index of all the workshops at the root of outputDir.
+func WriteIndexes(outputDir string, w *workshop.Workshop, fileName string) (*IndexResult, error) {
+	result := &IndexResult{}
+
+	workshopIndex, err := workshopIndexContents(outputDir, w, fileName)
+	if err != nil {
+		return result, err
+	}
+	if err := writeIndex(filepath.Join(outputDir, w.Slug(), fileName), workshopIndex, result); err != nil {
+		return result, err
+	}
+
+	rootIndex, err := rootIndexContents(outputDir, fileName)
+	if err != nil {
+		return result, err
+	}
+	if err := writeIndex(filepath.Join(outputDir, fileName), rootIndex, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func writeIndex(path string, contents string, result *IndexResult) error {
+	generated, err := isGeneratedIndex(path)
+	if err != nil {
+		return err
+	}
+	if !generated {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("'%s' was not generated by kody, not replacing it with the index of the saved solutions", path))
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		return fmt.Errorf("writing index '%s': %w", path, err)
+	}
+	result.Written = append(result.Written, path)
+
+	return nil
+}
+
+// isGeneratedIndex tells if the file at path was generated by kody, or doesn't exist yet.
+func isGeneratedIndex(path string) (bool, error) {
+	firstLine, err := readFirstLine(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading index '%s': %w", path, err)
+	}
+	return strings.HasPrefix(firstLine, indexMarker), nil
+}
+
+func readFirstLine(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	return scanner.Text(), scanner.Err()
+}
+
+func workshopIndexContents(outputDir string, w *workshop.Workshop, fileName string) (string, error) {
+	outline, err := w.Outline()
+	if err != nil {
+		return "", fmt.Errorf("loading outline of the workshop: %w", err)
+	}
+	exercises := outline.Exercises()
+
+	meta, err := json.Marshal(indexMeta{Title: w.Title(), Total: len(exercises)})
+	if err != nil {
+		return "", fmt.Errorf("encoding index metadata: %w", err)
+	}
+
+	var rows []string
+	for _, exercise := range exercises {
+		savedPath, err := FindSaved(outputDir, w, exercise.Section.Number, exercise.Number)
+		if errors.Is(err, ErrNotSaved) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("finding saved solution of exercise %s: %w", exercise.BreadCrumbs(), err)
+		}
+
+		info, err := os.Stat(savedPath)
+		if err != nil {
+			return "", fmt.Errorf("getting info for saved exercise: %w", err)
+		}
+
+		excerpt, err := notesExcerpt(savedPath)
+		if err != nil {
+			return "", err
+		}
+
+		link := markdown.Link(filepath.Join(outputDir, w.Slug()), savedPath)
+		rows = append(rows, fmt.Sprintf("| [%s](%s) | %s | %s |",
+			markdown.TableCell(exercise.BreadCrumbs()), link, info.ModTime().Format(indexDateFormat), markdown.TableCell(excerpt)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s -->\n", indexMarker, meta)
+	fmt.Fprintf(&b, "# %s\n\n", w.Title())
+	fmt.Fprintf(&b, "%s\n\n", progressLine(len(rows), len(exercises)))
+
+	if len(rows) == 0 {
+		b.WriteString("No exercises saved yet.\n")
+	} else {
+		b.WriteString("| Exercise | Last saved | Notes |\n| --- | --- | --- |\n")
+		b.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+
+	fmt.Fprintf(&b, "\n[All workshops](%s)\n", markdown.Link(filepath.Join(outputDir, w.Slug()), filepath.Join(outputDir, fileName)))
+
+	return b.String(), nil
+}
+
+func rootIndexContents(outputDir string, fileName string) (string, error) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return "", fmt.Errorf("reading output directory: %w", err)
+	}
+
+	var rows []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		workshopDir := filepath.Join(outputDir, entry.Name())
+
+		saved, lastSaved, err := savedExercises(workshopDir)
+		if err != nil {
+			return "", err
+		}
+		if saved == 0 {
+			continue
+		}
+
+		meta := indexMeta{Title: entry.Name()}
+		if firstLine, err := readFirstLine(filepath.Join(workshopDir, fileName)); err == nil && strings.HasPrefix(firstLine, indexMarker) {
+			rawMeta := strings.TrimSuffix(strings.TrimPrefix(firstLine, indexMarker), "-->")
+			// Indexes with unreadable metadata only lose the title and the total
+			_ = json.Unmarshal([]byte(strings.TrimSpace(rawMeta)), &meta)
+		}
+
+		progress := fmt.Sprintf("%d saved", saved)
+		if meta.Total > 0 {
+			progress = progressLine(saved, meta.Total)
+		}
+
+		link := markdown.Link(outputDir, filepath.Join(workshopDir, fileName))
+		rows = append(rows, fmt.Sprintf("| [%s](%s) | %s | %s |", markdown.TableCell(meta.Title), link, progress, lastSaved.Format(indexDateFormat)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s -->\n", indexMarker)
+	b.WriteString("# Solutions\n\n")
+
+	if len(rows) == 0 {
+		b.WriteString("No exercises saved yet.\n")
+	} else {
+		b.WriteString("| Workshop | Progress | Last saved |\n| --- | --- | --- |\n")
+		b.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// savedExercises counts the saved exercises in the folder of a workshop in the output directory, and
+// returns when the last one was saved.
+func savedExercises(workshopDir string) (int, time.Time, error) {
+	matches, err := filepath.Glob(filepath.Join(workshopDir, "[0-9][0-9].*", "[0-9][0-9].*"))
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("globbing files: %w", err)
+	}
+
+	var saved int
+	var lastSaved time.Time
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("getting info for saved exercise: %w", err)
+		}
+		if !info.IsDir() {
+			continue
+		}
+		saved++
+		if info.ModTime().After(lastSaved) {
+			lastSaved = info.ModTime()
+		}
+	}
+
+	return saved, lastSaved, nil
+}
+
+// notesExcerpt returns the first line of text of the notes kept with a saved exercise, shortened to
+// noteExcerptLength, or an empty string if there are no notes.
+func notesExcerpt(savedPath string) (string, error) {
+	for _, name := range workshop.NotesFileNames {
+		data, err := os.ReadFile(filepath.Join(savedPath, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading notes of saved exercise: %w", err)
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			// Headings are usually just "Notes", the excerpt is the first line of actual text
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			line = strings.TrimSpace(strings.TrimLeft(line, ">-* "))
+			if line == "" {
+				continue
+			}
+			if runes := []rune(line); len(runes) > noteExcerptLength {
+				line = string(runes[:noteExcerptLength-1]) + "…"
+			}
+			return line, nil
+		}
+		return "", nil
+	}
+
+	return "", nil
+}
+
+const progressBarWidth = 20
+
+// progressLine renders the completion of a workshop as a text progress bar, e.g. "`█████░░░` 3/8 (37%)".
+func progressLine(saved int, total int) string {
+	var filled, percent int
+	if total > 0 {
+		filled = min(saved*progressBarWidth/total, progressBarWidth)
+		percent = saved * 100 / total
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	return fmt.Sprintf("`%s` %d/%d (%d%%)", bar, saved, total, percent)
+}
@@ -0,0 +1,182 @@
+package solutions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/diff"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Labels of the versions of a file in the conflict markers left by MergeRestore.
+var mergeLabels = diff.MergeLabels{Ours: "playground", Theirs: "saved solution"}
+
+type MergeConflict struct {
+	Path   string `json:"path" yaml:"path"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// MergeResult lists the files of the playground changed by MergeRestore, by their path relative to the playground.
+type MergeResult struct {
+	// Updated files were only changed in the saved solution, they now have its version.
+	Updated []string `json:"updated" yaml:"updated"`
+	// Deleted files were deleted in the saved solution and not changed in the playground.
+	Deleted []string `json:"deleted" yaml:"deleted"`
+	// Merged files were changed in both, in different lines, and now have both changes.
+	Merged    []string        `json:"merged" yaml:"merged"`
+	Conflicts []MergeConflict `json:"conflicts" yaml:"conflicts"`
+}
+
+// MergeRestore restores a saved solution to the playground of the workshop without losing the changes
+// made in the playground, with a three-way merge of each file. basePath is the directory both started
+// from, usually the problem directory of the exercise. Files changed in both the playground and the
+// saved solution are merged line by line, and lines changed in both get conflict markers.
+func MergeRestore(w *workshop.Workshop, savedPath string, basePath string) (*MergeResult, error) {
+	result := &MergeResult{Updated: []string{}, Deleted: []string{}, Merged: []string{}, Conflicts: []MergeConflict{}}
+	playgroundPath := w.PlaygroundPath()
+
+	paths, err := mergePaths(basePath, playgroundPath, savedPath)
+	if err != nil {
+		return result, err
+	}
+
+	for _, path := range paths {
+		localPath := filepath.FromSlash(path)
+		base, err := readMergeFile(filepath.Join(basePath, localPath))
+		if err != nil {
+			return result, err
+		}
+		ours, err := readMergeFile(filepath.Join(playgroundPath, localPath))
+		if err != nil {
+			return result, err
+		}
+		theirs, err := readMergeFile(filepath.Join(savedPath, localPath))
+		if err != nil {
+			return result, err
+		}
+
+		if err := mergeFile(filepath.Join(playgroundPath, localPath), path, base, ours, theirs, result); err != nil {
+			return result, fmt.Errorf("merging '%s': %w", path, err)
+		}
+	}
+
+	return result, nil
+}
+
+func mergePaths(dirs ...string) ([]string, error) {
+	unique := make(map[string]bool)
+	for _, dir := range dirs {
+		if !directory.Exists(dir) {
+			continue
+		}
+		files, err := directory.Files(dir, workshop.IsDependencyOrCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			unique[file] = true
+		}
+	}
+
+	paths := make([]string, 0, len(unique))
+	for path := range unique {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func readMergeFile(path string) (*fileVersion, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading '%s': %w", path, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("getting info of '%s': %w", path, err)
+	}
+
+	return &fileVersion{data: data, mode: info.Mode().Perm()}, nil
+}
+
+// fileVersion is a version of a file in a merge. A nil version means the file doesn't exist in it.
+type fileVersion struct {
+	data []byte
+	mode os.FileMode
+}
+
+func sameVersion(a *fileVersion, b *fileVersion) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(a.data, b.data)
+}
+
+func mergeFile(dest string, path string, base *fileVersion, ours *fileVersion, theirs *fileVersion, result *MergeResult) error {
+	switch {
+	case sameVersion(ours, theirs), sameVersion(theirs, base):
+		// The playground already has the saved version, or the saved solution didn't change the file
+		return nil
+	case sameVersion(ours, base):
+		if theirs == nil {
+			result.Deleted = append(result.Deleted, path)
+			return os.Remove(dest)
+		}
+		result.Updated = append(result.Updated, path)
+		return writeVersion(dest, theirs, theirs.data)
+	}
+
+	// Both the playground and the saved solution changed the file
+	switch {
+	case ours == nil:
+		result.Conflicts = append(result.Conflicts, MergeConflict{Path: path, Reason: "deleted in the playground and changed in the saved solution, restored the saved version"})
+		return writeVersion(dest, theirs, theirs.data)
+	case theirs == nil:
+		result.Conflicts = append(result.Conflicts, MergeConflict{Path: path, Reason: "changed in the playground and deleted in the saved solution, kept the playground version"})
+		return nil
+	case diff.IsBinary(ours.data) || diff.IsBinary(theirs.data) || (base != nil && diff.IsBinary(base.data)):
+		result.Conflicts = append(result.Conflicts, MergeConflict{Path: path, Reason: "binary file changed in both, kept the playground version"})
+		return nil
+	}
+
+	var baseLines []string
+	if base != nil {
+		baseLines = diff.SplitLines(string(base.data))
+	}
+	merged, conflicts := diff.Merge3(baseLines, diff.SplitLines(string(ours.data)), diff.SplitLines(string(theirs.data)), mergeLabels)
+
+	text := strings.Join(merged, "\n")
+	if len(merged) > 0 && (bytes.HasSuffix(ours.data, []byte("\n")) || bytes.HasSuffix(theirs.data, []byte("\n"))) {
+		text += "\n"
+	}
+
+	if conflicts > 0 {
+		reason := fmt.Sprintf("%d conflicting changes, marked in the file", conflicts)
+		if conflicts == 1 {
+			reason = "1 conflicting change, marked in the file"
+		}
+		result.Conflicts = append(result.Conflicts, MergeConflict{Path: path, Reason: reason})
+	} else {
+		result.Merged = append(result.Merged, path)
+	}
+
+	return writeVersion(dest, ours, []byte(text))
+}
+
+// writeVersion writes data to dest, with the permissions of the version.
+func writeVersion(dest string, version *fileVersion, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, data, version.mode)
+}
@@ -0,0 +1,45 @@
+package solutions
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"os"
+	"path/filepath"
+)
+
+var ErrNotSaved = errors.New("no saved solution found for the exercise")
+
+// FindSaved returns the directory in outputDir with the saved solution of an exercise of the workshop.
+// The exercise is looked up by its section and exercise numbers only, so solutions are still found
+// after an exercise is renamed.
+func FindSaved(outputDir string, w *workshop.Workshop, sectionNo int, exerciseNo int) (string, error) {
+	sectionGlob := fmt.Sprintf("%02d.*", sectionNo)
+	exerciseGlob := fmt.Sprintf("%02d.*", exerciseNo)
+	searchGlob := filepath.Join(outputDir, w.Slug(), sectionGlob, exerciseGlob)
+
+	matches, err := filepath.Glob(searchGlob)
+	if err != nil {
+		return "", fmt.Errorf("globbing files: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%w (search glob: %s)", ErrNotSaved, searchGlob)
+	}
+
+	if len(matches) != 1 {
+		return "", fmt.Errorf("more than one candidate directory in the output directory found for the exercise was found")
+	}
+
+	return matches[0], nil
+}
+
+// Restore copies a saved solution to the playground of the workshop.
+func Restore(w *workshop.Workshop, savedPath string) error {
+	err := directory.CopyFS(w.PlaygroundPath(), os.DirFS(savedPath), nil)
+	if err != nil {
+		return fmt.Errorf("restoring files: %w", err)
+	}
+	return nil
+}
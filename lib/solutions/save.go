@@ -0,0 +1,184 @@
+package solutions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/cmder"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/testrun"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data available to the commit message template.
+type TemplateData struct {
+	Workshop *workshop.Workshop
+	Exercise *workshop.Exercise
+	// Tests is the result of the tests run before saving, nil if they didn't run.
+	Tests *testrun.Result
+}
+
+type SaveOptions struct {
+	OutputDir string
+	// Source is the directory to save. Defaults to the playground of the workshop.
+	Source string
+	// Commit tells if the saved exercise should be committed to the output directory git repository.
+	Commit bool
+	// CommitMessage is the template of the commit message, rendered with TemplateData.
+	CommitMessage *template.Template
+	// RequireTests runs the tests of the exercise in the playground first, and only saves it if they pass.
+	// The tests can only run when saving the playground, not another Source.
+	RequireTests bool
+	// TestOutput receives the output of the tests as they run. Defaults to discarding it.
+	TestOutput io.Writer
+	// IndexFile is the name of the index files regenerated after saving, in the output directory and in the
+	// folder of the workshop. No indexes are kept when empty.
+	IndexFile string
+}
+
+type SaveResult struct {
+	Destination string
+	Tests       *testrun.Result
+	// Copied tells if the exercise was copied to the destination, even if committing it failed after.
+	Copied bool
+	// Indexes are the index files regenerated after copying the exercise.
+	Indexes       []string
+	Committed     bool
+	CommitMessage string
+	GitOutput     string
+	Warnings      []string
+}
+
+// Save copies the playground of the workshop, or opts.Source, to the output directory, as the solution of the exercise,
+// and commits it if asked to.
+func Save(w *workshop.Workshop, exercise *workshop.Exercise, opts SaveOptions) (*SaveResult, error) {
+	result := &SaveResult{
+		Destination: workshop.DefaultExerciseDir(opts.OutputDir, w, exercise),
+	}
+
+	source := opts.Source
+	if source == "" {
+		source = w.PlaygroundPath()
+	}
+
+	if opts.RequireTests {
+		if source != w.PlaygroundPath() {
+			return result, &SaveError{Step: TestStep, Err: fmt.Errorf("tests can only run in the playground, not in '%s'", source)}
+		}
+
+		var err error
+		result.Tests, err = runTests(w, opts)
+		if err != nil {
+			return result, &SaveError{Step: TestStep, Err: err}
+		}
+		if !result.Tests.Passed {
+			return result, &SaveError{Step: TestStep, Err: fmt.Errorf("%w, not saving it (%s)", ErrTestsFailed, result.Tests.Summary())}
+		}
+	}
+
+	err := workshop.CopyExercise(source, result.Destination)
+	if err != nil {
+		return result, &SaveError{Step: CopyStep, Err: fmt.Errorf("error copying exercise %s > %s: %w", source, opts.OutputDir, err)}
+	}
+	result.Copied = true
+
+	if opts.IndexFile != "" {
+		indexes, err := WriteIndexes(opts.OutputDir, w, opts.IndexFile)
+		result.Indexes = indexes.Written
+		result.Warnings = append(result.Warnings, indexes.Warnings...)
+		if err != nil {
+			return result, &SaveError{Step: IndexStep, Err: fmt.Errorf("updating index of saved solutions: %w", err)}
+		}
+	}
+
+	if !opts.Commit {
+		return result, nil
+	}
+
+	commitMessageWriter := &strings.Builder{}
+	err = opts.CommitMessage.Execute(commitMessageWriter, TemplateData{Workshop: w, Exercise: exercise, Tests: result.Tests})
+	if err != nil {
+		return result, &SaveError{Step: TemplateStep, Err: fmt.Errorf("rendering commit message template: %w", err)}
+	}
+	result.CommitMessage = commitMessageWriter.String()
+
+	paths := append([]string{result.Destination}, result.Indexes...)
+	result.GitOutput, err = Commit(opts.OutputDir, paths, result.CommitMessage)
+	if err != nil {
+		return result, &SaveError{Step: CommitStep, Err: fmt.Errorf("committing exercise '%s': %w", result.Destination, err)}
+	}
+	result.Committed = true
+
+	return result, nil
+}
+
+func runTests(w *workshop.Workshop, opts SaveOptions) (*testrun.Result, error) {
+	command, err := testrun.DetectCommand(w)
+	if err != nil {
+		return nil, err
+	}
+
+	testOutput := opts.TestOutput
+	if testOutput == nil {
+		testOutput = io.Discard
+	}
+
+	return testrun.Run(context.Background(), command, testOutput)
+}
+
+var ErrTestsFailed = errors.New("the tests of the exercise failed")
+
+// SaveStep is the step of a save where an error happened.
+type SaveStep string
+
+const (
+	TestStep     SaveStep = "test"
+	CopyStep     SaveStep = "copy"
+	IndexStep    SaveStep = "index"
+	TemplateStep SaveStep = "template"
+	CommitStep   SaveStep = "commit"
+)
+
+type SaveError struct {
+	Step SaveStep
+	Err  error
+}
+
+func (e *SaveError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SaveError) Unwrap() error {
+	return e.Err
+}
+
+// Commit adds the given paths to the git repository at repoPath and commits them with the message.
+// Returns the output of the git commands.
+func Commit(repoPath string, paths []string, message string) (string, error) {
+	if !directory.IsGitRepo(repoPath) {
+		return "", fmt.Errorf("output directory '%s' is not a git repository", repoPath)
+	}
+
+	gitOutput := &strings.Builder{}
+
+	addArgs := append([]string{"-C", repoPath, "add", "-A", "--"}, paths...)
+	output, err := cmder.ExecuteCommand("git", addArgs...)
+	if err != nil {
+		gitOutput.WriteString(output)
+		return gitOutput.String(), fmt.Errorf("adding exercise to git repository: %w", err)
+	}
+
+	gitOutput.WriteString(output + "\n")
+
+	output, err = cmder.ExecuteCommand("git", "-C", repoPath, "commit", "-m", message)
+	if err != nil {
+		return gitOutput.String(), fmt.Errorf("committing exercise to git repository: %w", err)
+	}
+
+	gitOutput.WriteString(output + "\n")
+
+	return gitOutput.String(), nil
+}
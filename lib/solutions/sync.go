@@ -0,0 +1,300 @@
+package solutions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PlaygroundState is the last known state of the playground of a workshop: the exercise it was set to
+// and a snapshot of its files. It lets kody save the work on an exercise after the playground has
+// already been switched to another one.
+type PlaygroundState struct {
+	Hash          string    `json:"hash"`
+	SectionNumber int       `json:"sectionNumber"`
+	Number        int       `json:"number"`
+	BreadCrumbs   string    `json:"breadcrumbs"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	dir           string
+}
+
+func playgroundStateDir(stateDir string, w *workshop.Workshop) string {
+	return filepath.Join(stateDir, w.StorageName())
+}
+
+// LoadPlaygroundState loads the last known state of the playground of the workshop from stateDir.
+// Returns nil if the playground was never tracked.
+func LoadPlaygroundState(stateDir string, w *workshop.Workshop) (*PlaygroundState, error) {
+	dir := playgroundStateDir(stateDir, w)
+
+	data, err := os.ReadFile(filepath.Join(dir, "state.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading playground state: %w", err)
+	}
+
+	state := &PlaygroundState{dir: dir}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parsing playground state: %w", err)
+	}
+
+	return state, nil
+}
+
+// SnapshotPath returns the directory with the copy of the playground files of the state.
+func (s *PlaygroundState) SnapshotPath() string {
+	return filepath.Join(s.dir, "snapshot")
+}
+
+// TrackPlayground records the current playground of the workshop, set to exercise, as its last known state.
+func TrackPlayground(stateDir string, w *workshop.Workshop, exercise *workshop.Exercise) (*PlaygroundState, error) {
+	playgroundHash, err := w.PlaygroundHash()
+	if err != nil {
+		return nil, fmt.Errorf("getting playground hash: %w", err)
+	}
+
+	state := &PlaygroundState{
+		Hash:          playgroundHash,
+		SectionNumber: exercise.Section.Number,
+		Number:        exercise.Number,
+		BreadCrumbs:   exercise.BreadCrumbs(),
+		UpdatedAt:     time.Now(),
+		dir:           playgroundStateDir(stateDir, w),
+	}
+
+	// The new snapshot is copied next to the old one first, so a failed copy doesn't lose the previous state
+	tmpSnapshot := state.SnapshotPath() + ".tmp"
+	if err := os.RemoveAll(tmpSnapshot); err != nil {
+		return nil, fmt.Errorf("removing old temporary snapshot: %w", err)
+	}
+	if err := os.MkdirAll(tmpSnapshot, 0755); err != nil {
+		return nil, fmt.Errorf("creating playground snapshot: %w", err)
+	}
+	if err := directory.CopyFS(tmpSnapshot, os.DirFS(w.PlaygroundPath()), skipFromSnapshot); err != nil {
+		return nil, fmt.Errorf("copying playground snapshot: %w", err)
+	}
+	if err := os.RemoveAll(state.SnapshotPath()); err != nil {
+		return nil, fmt.Errorf("removing old playground snapshot: %w", err)
+	}
+	if err := os.Rename(tmpSnapshot, state.SnapshotPath()); err != nil {
+		return nil, fmt.Errorf("replacing playground snapshot: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding playground state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(state.dir, "state.json"), data, 0644); err != nil {
+		return nil, fmt.Errorf("writing playground state: %w", err)
+	}
+
+	return state, nil
+}
+
+type SyncOptions struct {
+	// StateDir is the directory where the last known state of the playgrounds is kept.
+	StateDir string
+	// Save holds the options used to save the previous exercise when the playground is switched.
+	Save SaveOptions
+	// ConfirmRestore is asked whether the saved solution at savedPath should be restored to the playground,
+	// after the playground is switched to exercise. When nil, saved solutions are never restored.
+	ConfirmRestore func(exercise *workshop.Exercise, savedPath string) (bool, error)
+	// CheckTests, when set, is called before saving the previous exercise and returns why it can't be saved,
+	// e.g. because its tests didn't pass. The tests can't run on the copy of the playground it is saved from.
+	CheckTests func(exercise *workshop.Exercise) error
+}
+
+type SyncResult struct {
+	// Exercise is the exercise currently in the playground.
+	Exercise *workshop.Exercise
+	// Previous is the last known state of the playground before the sync, nil if it was never tracked.
+	Previous *PlaygroundState
+	// Switched tells if the playground was switched to another exercise since the last known state.
+	Switched bool
+	// PreviousExercise is the exercise the playground was set to before the switch, if it still exists.
+	PreviousExercise *workshop.Exercise
+	// Saved is the result of saving the previous exercise, nil if it wasn't saved.
+	Saved *SaveResult
+	// SavedSolution is the saved solution of the current exercise, if there is one.
+	SavedSolution string
+	Restored      bool
+	// Warnings are the reasons the previous exercise wasn't saved or the saved solution wasn't restored.
+	Warnings []string
+}
+
+// Sync compares the playground of the workshop with its last known state. When the playground was
+// switched to another exercise, the previous exercise is saved from the snapshot of the last known state
+// and, if there is a saved solution for the new exercise, ConfirmRestore is asked whether to restore it.
+// The current playground then becomes the last known state.
+func Sync(w *workshop.Workshop, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{Warnings: []string{}}
+
+	exercise, err := w.PlaygroundExercise()
+	if err != nil {
+		return result, fmt.Errorf("detecting the exercise in the playground: %w", err)
+	}
+	result.Exercise = exercise
+
+	result.Previous, err = LoadPlaygroundState(opts.StateDir, w)
+	if err != nil {
+		return result, err
+	}
+
+	playgroundHash, err := w.PlaygroundHash()
+	if err != nil {
+		return result, fmt.Errorf("getting playground hash: %w", err)
+	}
+	result.Switched = result.Previous != nil && result.Previous.Hash != playgroundHash
+
+	if result.Switched {
+		if err := savePrevious(w, result, opts); err != nil {
+			return result, err
+		}
+		if err := restoreSaved(w, result, opts); err != nil {
+			return result, err
+		}
+	}
+
+	_, err = TrackPlayground(opts.StateDir, w, exercise)
+	if err != nil {
+		return result, fmt.Errorf("tracking playground: %w", err)
+	}
+
+	return result, nil
+}
+
+func savePrevious(w *workshop.Workshop, result *SyncResult, opts SyncOptions) error {
+	previous := result.Previous
+
+	exercise, err := w.LookupExerciseFromHash(previous.Hash)
+	if err != nil {
+		return fmt.Errorf("looking up previous exercise: %w", err)
+	}
+	if exercise == nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("previous exercise %s is no longer part of the workshop, not saving it", previous.BreadCrumbs))
+		return nil
+	}
+	result.PreviousExercise = exercise
+
+	if exercise.IsSolution() {
+		return nil
+	}
+
+	// Saving an untouched exercise would overwrite a solution saved before with the starting files
+	unchanged, err := directory.SameContents(previous.SnapshotPath(), exercise.Path(), workshop.IsDependencyOrCacheDir)
+	if err != nil {
+		return fmt.Errorf("comparing previous playground with the exercise: %w", err)
+	}
+	if unchanged {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("previous exercise %s was not changed, not saving it", exercise.BreadCrumbs()))
+		return nil
+	}
+
+	// A solution saved after the snapshot was taken, e.g. with kody save, is newer than the snapshot
+	newer, err := savedAfterSnapshot(w, exercise, previous, opts.Save.OutputDir)
+	if err != nil {
+		return err
+	}
+	if newer != "" {
+		result.Warnings = append(result.Warnings, newer)
+		return nil
+	}
+
+	if opts.CheckTests != nil {
+		if err := opts.CheckTests(exercise); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("not saving previous exercise %s: %v", exercise.BreadCrumbs(), err))
+			return nil
+		}
+	}
+
+	saveOpts := opts.Save
+	saveOpts.Source = previous.SnapshotPath()
+	result.Saved, err = Save(w, exercise, saveOpts)
+	if result.Saved != nil {
+		result.Warnings = append(result.Warnings, result.Saved.Warnings...)
+	}
+	if err != nil {
+		return fmt.Errorf("saving previous exercise %s: %w", exercise.BreadCrumbs(), err)
+	}
+
+	return nil
+}
+
+// savedAfterSnapshot checks if the saved solution of the previous exercise must be kept instead of being
+// replaced with the snapshot of the previous playground. Returns the reason it must be kept, or an empty
+// string if it can be replaced.
+func savedAfterSnapshot(w *workshop.Workshop, exercise *workshop.Exercise, previous *PlaygroundState, outputDir string) (string, error) {
+	savedPath := workshop.DefaultExerciseDir(outputDir, w, exercise)
+	if !directory.Exists(savedPath) {
+		return "", nil
+	}
+
+	same, err := directory.SameContents(previous.SnapshotPath(), savedPath, workshop.IsDependencyOrCacheDir)
+	if err != nil {
+		return "", fmt.Errorf("comparing previous playground with its saved solution: %w", err)
+	}
+	if same {
+		return fmt.Sprintf("previous exercise %s is already saved in '%s'", exercise.BreadCrumbs(), savedPath), nil
+	}
+
+	savedAt, err := directory.LatestModTime(savedPath, workshop.IsDependencyOrCacheDir)
+	if err != nil {
+		return "", err
+	}
+	if savedAt.After(previous.UpdatedAt) {
+		return fmt.Sprintf("the solution of previous exercise %s in '%s' was saved after the playground was last synced, not replacing it with the older files of the playground", exercise.BreadCrumbs(), savedPath), nil
+	}
+
+	return "", nil
+}
+
+func restoreSaved(w *workshop.Workshop, result *SyncResult, opts SyncOptions) error {
+	exercise := result.Exercise
+	if exercise.IsSolution() {
+		return nil
+	}
+
+	savedPath, err := FindSaved(opts.Save.OutputDir, w, exercise.Section.Number, exercise.Number)
+	if errors.Is(err, ErrNotSaved) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	result.SavedSolution = savedPath
+
+	if opts.ConfirmRestore == nil {
+		return nil
+	}
+
+	restore, err := opts.ConfirmRestore(exercise, savedPath)
+	if err != nil {
+		return err
+	}
+	if !restore {
+		return nil
+	}
+
+	if err := Restore(w, savedPath); err != nil {
+		return err
+	}
+	result.Restored = true
+
+	return nil
+}
+
+// skipFromSnapshot leaves the dependencies, caches and special files of the playground out of its snapshot.
+func skipFromSnapshot(_ string, d fs.DirEntry) bool {
+	if d.IsDir() {
+		return workshop.IsDependencyOrCacheDir(d.Name())
+	}
+	return !d.Type().IsRegular()
+}
@@ -0,0 +1,229 @@
+package solutions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"github.com/andrerfcsantos/kody/lib/testrun"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Sandbox is a temporary copy of a workshop, where saved solutions can be tested without touching the
+// real playground.
+type Sandbox struct {
+	Workshop *workshop.Workshop
+	// Original is the workshop the sandbox is a copy of.
+	Original *workshop.Workshop
+}
+
+// NewSandbox copies the workshop to a new temporary directory. Installed dependencies are not copied,
+// they are linked to the ones of the original workshop instead.
+func NewSandbox(w *workshop.Workshop) (*Sandbox, error) {
+	dir, err := os.MkdirTemp("", "kody-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("creating sandbox directory: %w", err)
+	}
+
+	// The copy keeps the folder name of the workshop, so things like slugs work the same way in the sandbox
+	sandboxPath := filepath.Join(dir, filepath.Base(w.Path))
+	if err := copyToSandbox(sandboxPath, w.Path); err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("copying workshop to sandbox: %w", err)
+	}
+
+	sandboxWorkshop, err := workshop.WorkshopFromPath(sandboxPath)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("loading sandbox workshop: %w", err)
+	}
+
+	return &Sandbox{Workshop: sandboxWorkshop, Original: w}, nil
+}
+
+// nodeModulesCaches are the folders where test runners and bundlers keep their caches in node_modules.
+var nodeModulesCaches = map[string]bool{".cache": true, ".vite": true, ".vite-temp": true, ".vitest": true}
+
+// copyToSandbox copies src to dst, leaving out .git folders. Dependencies are big, so instead of being
+// copied, each node_modules folder is recreated with links to the packages of the original one. Its
+// caches are left out, so the tests that run in the sandbox don't write to the original workshop.
+func copyToSandbox(dst string, src string) error {
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+
+	var nodeModules, links []string
+	err = directory.CopyFS(dst, os.DirFS(absSrc), func(path string, d fs.DirEntry) bool {
+		switch {
+		case d.IsDir() && d.Name() == "node_modules":
+			nodeModules = append(nodeModules, filepath.FromSlash(path))
+			return true
+		case d.IsDir():
+			return d.Name() == ".git"
+		case d.Type()&fs.ModeSymlink != 0:
+			links = append(links, filepath.FromSlash(path))
+			return true
+		}
+		return !d.Type().IsRegular()
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		target, err := os.Readlink(filepath.Join(absSrc, link))
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, filepath.Join(dst, link)); err != nil {
+			return err
+		}
+	}
+
+	for _, dir := range nodeModules {
+		if err := linkNodeModules(filepath.Join(dst, dir), filepath.Join(absSrc, dir)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func linkNodeModules(dst string, src string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, 0777); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if nodeModulesCaches[entry.Name()] {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove deletes the sandbox.
+func (s *Sandbox) Remove() error {
+	return os.RemoveAll(filepath.Dir(s.Workshop.Path))
+}
+
+// Materialize sets the playground of the sandbox to the exercise, as the workshop app does, and
+// restores the saved solution over it.
+func (s *Sandbox) Materialize(exercise *workshop.Exercise, savedPath string) error {
+	playgroundPath := s.Workshop.PlaygroundPath()
+
+	if err := os.RemoveAll(playgroundPath); err != nil {
+		return fmt.Errorf("removing sandbox playground: %w", err)
+	}
+	if err := copyToSandbox(playgroundPath, exercise.Path()); err != nil {
+		return fmt.Errorf("setting sandbox playground to the exercise: %w", err)
+	}
+
+	return Restore(s.Workshop, savedPath)
+}
+
+type VerifyResult struct {
+	Exercise  *workshop.Exercise
+	SavedPath string
+	Tests     *testrun.Result
+	// Err is set when the tests couldn't run.
+	Err error
+}
+
+func (r *VerifyResult) Passed() bool {
+	return r.Err == nil && r.Tests != nil && r.Tests.Passed
+}
+
+type VerifyOptions struct {
+	OutputDir string
+	// TestOutput receives the output of the tests as they run. Defaults to discarding it.
+	TestOutput io.Writer
+	// OnResult is called after each exercise is verified.
+	OnResult func(*VerifyResult)
+}
+
+// Verify runs the tests of the saved solutions of the exercises in the sandbox, one at a time. Exercises
+// without a saved solution are skipped.
+func (s *Sandbox) Verify(ctx context.Context, exercises []*workshop.Exercise, opts VerifyOptions) ([]*VerifyResult, error) {
+	testOutput := opts.TestOutput
+	if testOutput == nil {
+		testOutput = io.Discard
+	}
+
+	var results []*VerifyResult
+	for _, exercise := range exercises {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		if exercise.IsSolution() {
+			continue
+		}
+
+		savedPath, err := FindSaved(opts.OutputDir, s.Original, exercise.Section.Number, exercise.Number)
+		if errors.Is(err, ErrNotSaved) {
+			continue
+		}
+
+		result := &VerifyResult{Exercise: exercise, SavedPath: savedPath, Err: err}
+		if result.Err == nil {
+			result.Tests, result.Err = s.verifyOne(ctx, exercise, savedPath, testOutput)
+		}
+
+		results = append(results, result)
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+	}
+
+	return results, nil
+}
+
+func (s *Sandbox) verifyOne(ctx context.Context, exercise *workshop.Exercise, savedPath string, testOutput io.Writer) (*testrun.Result, error) {
+	if err := s.Materialize(exercise, savedPath); err != nil {
+		return nil, err
+	}
+
+	command, err := testrun.DetectCommand(s.Workshop)
+	if err != nil {
+		return nil, err
+	}
+
+	return testrun.Run(ctx, command, testOutput)
+}
+
+// VerifySummary counts the results of a verification.
+type VerifySummary struct {
+	Passed   int           `json:"passed" yaml:"passed"`
+	Failed   int           `json:"failed" yaml:"failed"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+func SummarizeVerify(results []*VerifyResult) VerifySummary {
+	var summary VerifySummary
+	for _, result := range results {
+		if result.Passed() {
+			summary.Passed++
+		} else {
+			summary.Failed++
+		}
+		if result.Tests != nil {
+			summary.Duration += result.Tests.Duration
+		}
+	}
+	return summary
+}
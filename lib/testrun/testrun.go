@@ -0,0 +1,242 @@
+package testrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNoTests = errors.New("no way to run the tests was found")
+
+// Source tells where the command to run the tests came from.
+type Source string
+
+const (
+	FromPlaygroundScript Source = "playground package.json test script"
+	FromWorkshopScript   Source = "workshop package.json test script"
+	FromTestFiles        Source = "test files in the playground"
+)
+
+// Command is the command that runs the tests of the exercise in the playground.
+type Command struct {
+	Program string
+	Args    []string
+	Dir     string
+	Source  Source
+}
+
+func (c *Command) String() string {
+	return strings.Join(append([]string{c.Program}, c.Args...), " ")
+}
+
+// TestFilePatterns are the names of the files recognized as test files.
+var TestFilePatterns = []string{"*.test.js", "*.test.jsx", "*.test.mjs", "*.test.ts", "*.test.tsx", "*.spec.js", "*.spec.jsx", "*.spec.mjs", "*.spec.ts", "*.spec.tsx"}
+
+// DetectCommand finds how to run the tests of the playground of the workshop. In order, it uses the test
+// script of the playground package.json, the test script of the workshop package.json, and the test
+// files in the playground.
+func DetectCommand(w *workshop.Workshop) (*Command, error) {
+	playgroundPath := w.PlaygroundPath()
+
+	if config, err := workshop.LoadPackageConfig(playgroundPath); err == nil && config.Scripts["test"] != "" {
+		return &Command{Program: "npm", Args: []string{"run", "test"}, Dir: playgroundPath, Source: FromPlaygroundScript}, nil
+	}
+
+	if config, err := workshop.LoadPackageConfig(w.Path); err == nil && config.Scripts["test"] != "" {
+		return &Command{Program: "npm", Args: []string{"run", "test"}, Dir: w.Path, Source: FromWorkshopScript}, nil
+	}
+
+	testFiles, err := FindTestFiles(playgroundPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(testFiles) == 0 {
+		return nil, fmt.Errorf("%w: the playground has no test files and no package.json has a test script", ErrNoTests)
+	}
+
+	for _, file := range testFiles {
+		if ext := filepath.Ext(file); ext == ".ts" || ext == ".tsx" {
+			return &Command{Program: "npx", Args: []string{"--yes", "vitest", "run"}, Dir: playgroundPath, Source: FromTestFiles}, nil
+		}
+	}
+
+	// Plain JavaScript tests can run with the test runner built into node
+	return &Command{Program: "node", Args: append([]string{"--test"}, testFiles...), Dir: playgroundPath, Source: FromTestFiles}, nil
+}
+
+// FindTestFiles returns the test files in dir, relative to it.
+func FindTestFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || workshop.IsDependencyOrCacheDir(d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		for _, pattern := range TestFilePatterns {
+			if ok, _ := filepath.Match(pattern, d.Name()); ok {
+				rel, err := filepath.Rel(dir, path)
+				if err != nil {
+					return err
+				}
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("looking for test files in '%s': %w", dir, err)
+	}
+
+	return files, nil
+}
+
+type Result struct {
+	Command   string        `json:"command" yaml:"command"`
+	Passed    bool          `json:"passed" yaml:"passed"`
+	ExitCode  int           `json:"exitCode" yaml:"exitCode"`
+	Counts    *Counts       `json:"counts,omitempty" yaml:"counts,omitempty"`
+	StartedAt time.Time     `json:"startedAt" yaml:"startedAt"`
+	Duration  time.Duration `json:"duration" yaml:"duration"`
+	Output    string        `json:"-" yaml:"-"`
+}
+
+// Summary returns a one line description of the result, e.g. "3 passed, 1 failed".
+func (r *Result) Summary() string {
+	status := "passed"
+	if !r.Passed {
+		status = "failed"
+	}
+
+	if r.Counts == nil {
+		return fmt.Sprintf("tests %s (exit code %d)", status, r.ExitCode)
+	}
+	return fmt.Sprintf("tests %s: %d passed, %d failed", status, r.Counts.Passed, r.Counts.Failed)
+}
+
+// Run runs the command, writing its output to stream as it is produced. An error is only returned if
+// the command couldn't be run, failing tests are reported in the result.
+func Run(ctx context.Context, command *Command, stream io.Writer) (*Result, error) {
+	result := &Result{Command: command.String(), StartedAt: time.Now()}
+
+	captured := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, command.Program, command.Args...)
+	cmd.Dir = command.Dir
+	cmd.Stdout = io.MultiWriter(stream, captured)
+	cmd.Stderr = io.MultiWriter(stream, captured)
+	cmd.Stdin = nil
+	// Ask the test runners not to wait for changes
+	cmd.Env = append(os.Environ(), "CI=true")
+
+	err := cmd.Run()
+	result.Duration = time.Since(result.StartedAt)
+	result.Output = captured.String()
+	result.Counts = ParseCounts(result.Output)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return nil, fmt.Errorf("running '%s': %w", command, err)
+	}
+
+	result.Passed = result.ExitCode == 0 && (result.Counts == nil || result.Counts.Failed == 0)
+	return result, nil
+}
+
+type Counts struct {
+	Passed int `json:"passed" yaml:"passed"`
+	Failed int `json:"failed" yaml:"failed"`
+}
+
+var (
+	ansiEscapes = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+	// The Tests line of vitest, "Tests  1 failed | 3 passed (4)", and jest, "Tests:  1 failed, 3 passed, 4 total",
+	// has all the counts of a run. The Test Files and Test Suites lines next to it count files, not tests.
+	testsLine   = regexp.MustCompile(`^Tests:?\s+(.*\d.*)$`)
+	testsCounts = regexp.MustCompile(`(\d+) (passed|failed)\b`)
+	// Other runners print each count on its own line. mocha: "3 passing (14ms)", playwright: "1 failed",
+	// node: "ℹ pass 3", TAP: "# fail 1".
+	countLine = regexp.MustCompile(`^(?:(\d+) (passing|failing|passed|failed)\b|[#ℹ] (pass|fail) (\d+)$)`)
+)
+
+// ParseCounts extracts the number of passed and failed tests from the summary the common test runners
+// print at the end of a run. When the output has more than one summary, e.g. a script that runs the tests
+// twice, the counts of the last one are used. Returns nil if no summary was found.
+func ParseCounts(output string) *Counts {
+	var counts *Counts
+	// hasPassed and hasFailed tell which counts of the current summary were found, finding one of them
+	// again starts a new summary, so counts don't carry over from one summary to the next.
+	var hasPassed, hasFailed bool
+
+	for _, line := range strings.Split(ansiEscapes.ReplaceAllString(output, ""), "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := testsLine.FindStringSubmatch(line); match != nil {
+			summary := &Counts{}
+			for _, count := range testsCounts.FindAllStringSubmatch(match[1], -1) {
+				n, _ := strconv.Atoi(count[1])
+				if count[2] == "passed" {
+					summary.Passed = n
+				} else {
+					summary.Failed = n
+				}
+			}
+			counts, hasPassed, hasFailed = summary, true, true
+			continue
+		}
+
+		passed, n, ok := parseCountLine(line)
+		if !ok {
+			continue
+		}
+
+		if counts == nil || (passed && hasPassed) || (!passed && hasFailed) {
+			counts, hasPassed, hasFailed = &Counts{}, false, false
+		}
+		if passed {
+			counts.Passed, hasPassed = n, true
+		} else {
+			counts.Failed, hasFailed = n, true
+		}
+	}
+
+	return counts
+}
+
+// parseCountLine parses a line with a single count, telling if it is the count of passed or failed tests.
+func parseCountLine(line string) (passed bool, n int, ok bool) {
+	match := countLine.FindStringSubmatch(line)
+	if match == nil {
+		return false, 0, false
+	}
+
+	number, kind := match[1], match[2]
+	if number == "" {
+		number, kind = match[4], match[3]
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return false, 0, false
+	}
+	return strings.HasPrefix(kind, "pass"), n, true
+}
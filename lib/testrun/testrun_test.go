@@ -0,0 +1,76 @@
+package testrun
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCounts(t *testing.T) {
+	tests := []struct {
+		// file is the output of a test runner in testdata
+		file string
+		want *Counts
+	}{
+		{file: "vitest-failed.txt", want: &Counts{Passed: 4, Failed: 1}},
+		// A test file that fails to load is counted in Test Files, not in Tests
+		{file: "vitest-file-failed.txt", want: &Counts{Passed: 2, Failed: 0}},
+		{file: "vitest-passed-color.txt", want: &Counts{Passed: 5, Failed: 0}},
+		{file: "jest-failed.txt", want: &Counts{Passed: 3, Failed: 1}},
+		{file: "jest-suite-failed.txt", want: &Counts{Passed: 2, Failed: 0}},
+		{file: "mocha-failed.txt", want: &Counts{Passed: 3, Failed: 1}},
+		// The failing count of the first run doesn't carry over to the second one
+		{file: "mocha-two-runs.txt", want: &Counts{Passed: 2, Failed: 0}},
+		{file: "node-tap.txt", want: &Counts{Passed: 3, Failed: 1}},
+		{file: "node-tap-skipped.txt", want: &Counts{Passed: 1, Failed: 0}},
+		{file: "node-spec-color.txt", want: &Counts{Passed: 3, Failed: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			output, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := ParseCounts(string(output))
+			if got == nil {
+				t.Fatalf("ParseCounts() = nil, want %+v", *tt.want)
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseCounts() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCountsSummaries(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *Counts
+	}{
+		{name: "empty", output: "", want: nil},
+		{name: "no summary", output: "sh: 1: vitest: not found\n", want: nil},
+		{name: "vitest without tests", output: " Test Files  1 failed (1)\n      Tests  no tests\n", want: nil},
+		{name: "playwright", output: "  1 failed\n    [chromium] › example.spec.ts:3:5 › has title\n  2 passed (3.1s)\n", want: &Counts{Passed: 2, Failed: 1}},
+		{name: "passed count of a run is not reused", output: "  3 passing (5ms)\n  1 failing\n\n  2 failing\n", want: &Counts{Passed: 0, Failed: 2}},
+		{name: "line counts after a Tests line", output: "      Tests  1 failed | 3 passed (4)\n# pass 2\n# fail 0\n", want: &Counts{Passed: 2, Failed: 0}},
+		{name: "Tests line after line counts", output: "  3 passing (5ms)\n  1 failing\n      Tests  5 passed (5)\n", want: &Counts{Passed: 5, Failed: 0}},
+		// Counts in the middle of a line are not summaries, e.g. vitest lines for each file
+		{name: "counts of a file", output: " ❯ src/counter.test.tsx  (3 tests | 1 failed) 32ms\n", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCounts(tt.output)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Errorf("ParseCounts() = %v, want %v", got, tt.want)
+			case *got != *tt.want:
+				t.Errorf("ParseCounts() = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/workshop"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher watches the playgrounds of a set of workshops and reports when the changes in each of them settle.
+type Watcher struct {
+	fsWatcher *fsnotify.Watcher
+	debounce  time.Duration
+	workshops []*workshop.Workshop
+	timers    map[*workshop.Workshop]*time.Timer
+	// generations count the changes to each workshop, so settle events of timers that were replaced
+	// after they fired can be told apart from the current one
+	generations map[*workshop.Workshop]uint64
+	settled     chan settleEvent
+	done        chan struct{}
+}
+
+type settleEvent struct {
+	workshop   *workshop.Workshop
+	generation uint64
+}
+
+func New(workshops []*workshop.Workshop, debounce time.Duration) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	// Longer paths first, so nested workshops are matched before their parents
+	sorted := append([]*workshop.Workshop(nil), workshops...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].Path) > len(sorted[j].Path)
+	})
+
+	w := &Watcher{
+		fsWatcher:   fsWatcher,
+		debounce:    debounce,
+		workshops:   sorted,
+		timers:      make(map[*workshop.Workshop]*time.Timer),
+		generations: make(map[*workshop.Workshop]uint64),
+		settled:     make(chan settleEvent),
+		done:        make(chan struct{}),
+	}
+
+	for _, ws := range sorted {
+		// The workshop folder is watched to notice when the playground is replaced
+		if err := fsWatcher.Add(ws.Path); err != nil {
+			fsWatcher.Close()
+			return nil, fmt.Errorf("watching workshop '%s': %w", ws.Path, err)
+		}
+
+		if ws.HasPlayground() {
+			if err := w.addRecursive(ws.PlaygroundPath()); err != nil {
+				fsWatcher.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// IsIgnored reports whether changes to a path inside the playground should be ignored: dependencies,
+// build output, caches, hidden files and editor temporary files.
+func IsIgnored(playgroundPath string, path string) bool {
+	rel, err := filepath.Rel(playgroundPath, path)
+	if err != nil {
+		return true
+	}
+
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if part == "." {
+			continue
+		}
+		if strings.HasPrefix(part, ".") || workshop.IsDependencyOrCacheDir(part) {
+			return true
+		}
+	}
+
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, "~") || strings.HasSuffix(name, ".swp") || strings.HasSuffix(name, ".tmp")
+}
+
+func (w *Watcher) addRecursive(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may be gone by the time it is walked
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != dir && IsIgnored(dir, path) {
+			return filepath.SkipDir
+		}
+
+		if err := w.fsWatcher.Add(path); err != nil {
+			return fmt.Errorf("watching '%s': %w", path, err)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) workshopOf(path string) *workshop.Workshop {
+	for _, ws := range w.workshops {
+		if path == ws.Path || strings.HasPrefix(path, ws.Path+string(filepath.Separator)) {
+			return ws
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) handleEvent(event fsnotify.Event) {
+	ws := w.workshopOf(event.Name)
+	if ws == nil {
+		return
+	}
+
+	playgroundPath := ws.PlaygroundPath()
+	if event.Name != playgroundPath && !strings.HasPrefix(event.Name, playgroundPath+string(filepath.Separator)) {
+		return
+	}
+
+	if IsIgnored(playgroundPath, event.Name) {
+		return
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// Errors are ignored, the directory may have been removed already
+			_ = w.addRecursive(event.Name)
+		}
+	}
+
+	if timer, ok := w.timers[ws]; ok {
+		timer.Stop()
+	}
+	w.generations[ws]++
+	settle := settleEvent{workshop: ws, generation: w.generations[ws]}
+	w.timers[ws] = time.AfterFunc(w.debounce, func() {
+		select {
+		case w.settled <- settle:
+		case <-w.done:
+		}
+	})
+}
+
+// Run watches the playgrounds until the context is cancelled, calling onSettled every time the changes
+// to the playground of a workshop settle. onSettled is never called concurrently. Errors from the
+// underlying file watcher are passed to onError.
+func (w *Watcher) Run(ctx context.Context, onSettled func(*workshop.Workshop), onError func(error)) error {
+	defer w.fsWatcher.Close()
+	defer close(w.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for _, timer := range w.timers {
+				timer.Stop()
+			}
+			return nil
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return nil
+			}
+			w.handleEvent(event)
+		case err, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return nil
+			}
+			onError(err)
+		case settle := <-w.settled:
+			// The timer may have fired while a newer change was replacing it
+			if settle.generation != w.generations[settle.workshop] {
+				continue
+			}
+			delete(w.timers, settle.workshop)
+			onSettled(settle.workshop)
+		}
+	}
+}
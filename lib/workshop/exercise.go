@@ -6,17 +6,28 @@ import (
 	"github.com/andrerfcsantos/kody/lib/hash"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
+)
+
+// ExerciseState tells if an exercise directory holds the problem or the official solution of the exercise.
+type ExerciseState string
+
+const (
+	ProblemState  ExerciseState = "problem"
+	SolutionState ExerciseState = "solution"
 )
 
 type Exercise struct {
 	Number  int
 	Slug    string
 	Section Section
+	State   ExerciseState
 	path    string
 }
 
+func (e *Exercise) IsSolution() bool {
+	return e.State == SolutionState
+}
+
 func (e *Exercise) BreadCrumbs() string {
 	return fmt.Sprintf("[%0.2d] %s > [%0.2d] %s", e.Section.Number, e.Section.Slug, e.Number, e.Slug)
 }
@@ -45,6 +56,14 @@ func (e *Exercise) Path() string {
 	return e.path
 }
 
+// Title returns the title of the exercise from its README.mdx, falling back to the slug.
+func (e *Exercise) Title() string {
+	if title, err := TitleFromMDX(filepath.Join(e.path, "README.mdx")); err == nil && title != "" {
+		return title
+	}
+	return e.Slug
+}
+
 func HashFromPath(exerciseDir string) (s string, err error) {
 	readmePath := filepath.Join(exerciseDir, "README.mdx")
 
@@ -64,43 +83,37 @@ func HashFromPath(exerciseDir string) (s string, err error) {
 	return hash.MD5Hex(readmeData), nil
 }
 
+// ExerciseFromPath parses an exercise from the path of its directory, in the format [...]/<section>/<exercise>.
+// Returns a *NameError if the section or exercise directory names are not valid epicshop names.
 func ExerciseFromPath(exercisePath string) (*Exercise, error) {
-	parts := strings.Split(exercisePath, string(filepath.Separator))
-	if len(parts) < 2 {
-		return nil, fmt.Errorf("exercise path '%s' does not seem to contain [...]/<section>/<exercise>", exercisePath)
-	}
-	parts = parts[len(parts)-2:]
+	exerciseName := filepath.Base(exercisePath)
+	sectionPath := filepath.Dir(exercisePath)
+	sectionName := filepath.Base(sectionPath)
 
-	sectionParts := strings.Split(parts[0], ".")
-	if len(sectionParts) < 2 {
-		return nil, fmt.Errorf("section path '%s' does not seem to be in the format [...]/<section>.<number>/<exercise>", parts[0])
+	if sectionPath == "." || sectionName == string(filepath.Separator) {
+		return nil, fmt.Errorf("exercise path '%s' does not seem to contain [...]/<section>/<exercise>", exercisePath)
 	}
 
-	sectionNumber, err := strconv.Atoi(sectionParts[0])
+	parsedSection, err := ParseSectionName(sectionName)
 	if err != nil {
-		return nil, fmt.Errorf("section number '%s' is not a number", sectionParts[1])
-	}
-
-	section := Section{
-		Number: sectionNumber,
-		Slug:   sectionParts[1],
-	}
-
-	exerciseParts := strings.Split(parts[1], ".")
-	if len(exerciseParts) < 3 {
-		return nil, fmt.Errorf("exercise path '%s' does not seem to be in the format [...]/<section>/<exercise>.problem.<number>", exercisePath)
+		return nil, err
 	}
 
-	exerciseNumber, err := strconv.Atoi(exerciseParts[0])
+	parsedExercise, err := ParseExerciseName(exerciseName)
 	if err != nil {
-		return nil, fmt.Errorf("exercise number '%s' is not a number", exerciseParts[1])
+		return nil, err
 	}
 
 	exercise := Exercise{
-		Number:  exerciseNumber,
-		Slug:    exerciseParts[2],
-		Section: section,
-		path:    exercisePath,
+		Number: parsedExercise.Number,
+		Slug:   parsedExercise.Slug,
+		Section: Section{
+			Number: parsedSection.Number,
+			Slug:   parsedSection.Slug,
+			path:   sectionPath,
+		},
+		State: parsedExercise.State,
+		path:  exercisePath,
 	}
 
 	return &exercise, nil
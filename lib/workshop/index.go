@@ -0,0 +1,213 @@
+package workshop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/hash"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const hashIndexVersion = 1
+
+// HashIndex is an on-disk cache of the README.mdx hashes of the exercises of a workshop.
+// Entries are invalidated whenever the size or the modification time of the README.mdx changes.
+type HashIndex struct {
+	Version int                       `json:"version"`
+	Entries map[string]HashIndexEntry `json:"entries"`
+	path    string
+	dirty   bool
+	mu      sync.Mutex
+}
+
+type HashIndexEntry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Hash    string    `json:"hash"`
+}
+
+// HashIndexPath returns the path of the index file for the workshop inside indexDir.
+func HashIndexPath(indexDir string, w *Workshop) string {
+	return filepath.Join(indexDir, w.StorageName()+".json")
+}
+
+// StorageName returns a name that identifies the workshop in kody's data folder. It is made of the slug
+// of the workshop and a hash of its path, so two copies of the same workshop don't share their data.
+func (w *Workshop) StorageName() string {
+	absPath, err := filepath.Abs(w.Path)
+	if err != nil {
+		absPath = w.Path
+	}
+
+	name := hash.MD5Hex([]byte(absPath))[:12]
+	if slug := w.Slug(); slug != "" {
+		name = slug + "-" + name
+	}
+
+	return name
+}
+
+func LoadHashIndex(indexPath string) (*HashIndex, error) {
+	index := &HashIndex{
+		Version: hashIndexVersion,
+		Entries: make(map[string]HashIndexEntry),
+		path:    indexPath,
+	}
+
+	data, err := os.ReadFile(indexPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading hash index '%s': %w", indexPath, err)
+	}
+
+	var stored HashIndex
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != hashIndexVersion {
+		// A corrupt or outdated index is simply rebuilt
+		return index, nil
+	}
+
+	if stored.Entries != nil {
+		index.Entries = stored.Entries
+	}
+
+	return index, nil
+}
+
+// Hash returns the README.mdx hash of the exercise directory, computing it only if the
+// cached entry is missing or stale. It is safe for concurrent use.
+func (i *HashIndex) Hash(exerciseDir string) (string, error) {
+	readmePath := filepath.Join(exerciseDir, "README.mdx")
+
+	info, err := os.Stat(readmePath)
+	if err != nil {
+		return "", fmt.Errorf("getting info for '%s' file: %w", readmePath, err)
+	}
+
+	i.mu.Lock()
+	entry, ok := i.Entries[readmePath]
+	i.mu.Unlock()
+	if ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry.Hash, nil
+	}
+
+	h, err := HashFromPath(exerciseDir)
+	if err != nil {
+		return "", err
+	}
+
+	i.mu.Lock()
+	i.Entries[readmePath] = HashIndexEntry{
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Hash:    h,
+	}
+	i.dirty = true
+	i.mu.Unlock()
+
+	return h, nil
+}
+
+// Prune removes the entries whose README.mdx no longer exists, e.g. of exercises that were deleted or renamed.
+func (i *HashIndex) Prune() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for readmePath := range i.Entries {
+		if _, err := os.Stat(readmePath); err != nil {
+			delete(i.Entries, readmePath)
+			i.dirty = true
+		}
+	}
+}
+
+func (i *HashIndex) Len() int {
+	return len(i.Entries)
+}
+
+func (i *HashIndex) Path() string {
+	return i.path
+}
+
+// Save writes the index to disk if it changed since it was loaded.
+func (i *HashIndex) Save() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.dirty {
+		return nil
+	}
+
+	err := os.MkdirAll(filepath.Dir(i.path), 0750)
+	if err != nil {
+		return fmt.Errorf("creating hash index dir: %w", err)
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return fmt.Errorf("marshaling hash index: %w", err)
+	}
+
+	err = os.WriteFile(i.path, data, 0640)
+	if err != nil {
+		return fmt.Errorf("writing hash index '%s': %w", i.path, err)
+	}
+
+	i.dirty = false
+	return nil
+}
+
+// UseHashIndex makes the workshop use the persistent hash index stored in indexDir for exercise lookups.
+func (w *Workshop) UseHashIndex(indexDir string) error {
+	index, err := LoadHashIndex(HashIndexPath(indexDir, w))
+	if err != nil {
+		return err
+	}
+	index.Prune()
+	w.index = index
+	return nil
+}
+
+// RebuildHashIndex discards all the cached hashes and hashes every exercise of the workshop again.
+func (w *Workshop) RebuildHashIndex(indexDir string) (*HashIndex, error) {
+	index := &HashIndex{
+		Version: hashIndexVersion,
+		Entries: make(map[string]HashIndexEntry),
+		path:    HashIndexPath(indexDir, w),
+		dirty:   true,
+	}
+
+	exercisePaths, err := w.exercisePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(exercisePaths))
+	parallelFor(len(exercisePaths), func(i int) {
+		_, errs[i] = index.Hash(exercisePaths[i])
+	})
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("hashing exercise at '%s': %w", exercisePaths[i], err)
+		}
+	}
+
+	if err := index.Save(); err != nil {
+		return nil, err
+	}
+
+	w.index = index
+	return index, nil
+}
+
+func (w *Workshop) exerciseHash(exerciseDir string) (string, error) {
+	if w.index == nil {
+		return HashFromPath(exerciseDir)
+	}
+	return w.index.Hash(exerciseDir)
+}
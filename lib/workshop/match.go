@@ -0,0 +1,238 @@
+package workshop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNoExactMatch is returned when the playground README.mdx does not match the README.mdx of any exercise byte for byte.
+var ErrNoExactMatch = errors.New("no exercise found for playground hash")
+
+const (
+	readmeWeight      = 0.6
+	packageNameWeight = 0.2
+	fileTreeWeight    = 0.2
+)
+
+// MatchScores holds the individual similarity signals between the playground and an exercise directory.
+// Each score is between 0 and 1. A negative score means the signal was not available for the comparison.
+type MatchScores struct {
+	Readme      float64
+	PackageName float64
+	FileTree    float64
+}
+
+// ExerciseMatch is a candidate exercise for the contents of the playground.
+type ExerciseMatch struct {
+	Exercise   *Exercise
+	Confidence float64
+	Scores     MatchScores
+}
+
+func (m *ExerciseMatch) Explain() string {
+	var parts []string
+	if m.Scores.Readme >= 0 {
+		parts = append(parts, fmt.Sprintf("README.mdx %.0f%%", m.Scores.Readme*100))
+	}
+	if m.Scores.PackageName >= 0 {
+		parts = append(parts, fmt.Sprintf("package.json name %.0f%%", m.Scores.PackageName*100))
+	}
+	if m.Scores.FileTree >= 0 {
+		parts = append(parts, fmt.Sprintf("file tree %.0f%%", m.Scores.FileTree*100))
+	}
+	return strings.Join(parts, ", ")
+}
+
+type dirFingerprint struct {
+	readmeLines []string
+	packageName string
+	files       map[string]bool
+}
+
+func fingerprintDir(dir string) (*dirFingerprint, error) {
+	fp := &dirFingerprint{files: make(map[string]bool)}
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.mdx"))
+	if err == nil {
+		fp.readmeLines = normalizedLines(string(readme))
+	}
+
+	pkgData, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err == nil {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(pkgData, &pkg) == nil {
+			fp.packageName = pkg.Name
+		}
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && IsDependencyOrCacheDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		fp.files[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking '%s': %w", dir, err)
+	}
+
+	return fp, nil
+}
+
+func normalizedLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// linesSimilarity computes the Dice coefficient between two multisets of lines.
+func linesSimilarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	counts := make(map[string]int, len(a))
+	for _, line := range a {
+		counts[line]++
+	}
+
+	common := 0
+	for _, line := range b {
+		if counts[line] > 0 {
+			counts[line]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// setSimilarity computes the Jaccard index between two sets of file paths.
+func setSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func scoreFingerprints(playground, exercise *dirFingerprint) (MatchScores, float64) {
+	scores := MatchScores{Readme: -1, PackageName: -1, FileTree: -1}
+	var total, weights float64
+
+	if len(playground.readmeLines) > 0 || len(exercise.readmeLines) > 0 {
+		scores.Readme = linesSimilarity(playground.readmeLines, exercise.readmeLines)
+		total += scores.Readme * readmeWeight
+		weights += readmeWeight
+	}
+
+	if playground.packageName != "" || exercise.packageName != "" {
+		scores.PackageName = 0
+		if playground.packageName == exercise.packageName {
+			scores.PackageName = 1
+		}
+		total += scores.PackageName * packageNameWeight
+		weights += packageNameWeight
+	}
+
+	if len(playground.files) > 0 || len(exercise.files) > 0 {
+		scores.FileTree = setSimilarity(playground.files, exercise.files)
+		total += scores.FileTree * fileTreeWeight
+		weights += fileTreeWeight
+	}
+
+	if weights == 0 {
+		return scores, 0
+	}
+
+	return scores, total / weights
+}
+
+// MatchPlayground scores every exercise of the workshop by similarity with the current playground
+// and returns the candidates sorted by decreasing confidence.
+func (w *Workshop) MatchPlayground() ([]ExerciseMatch, error) {
+	if !w.HasPlayground() {
+		return nil, fmt.Errorf("workshop '%s' does not have a playground folder", w.Path)
+	}
+
+	playground, err := fingerprintDir(w.PlaygroundPath())
+	if err != nil {
+		return nil, fmt.Errorf("fingerprinting playground: %w", err)
+	}
+
+	exercisePaths, err := w.exercisePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []ExerciseMatch
+	for _, path := range exercisePaths {
+		exercise, err := ExerciseFromPath(path)
+		if err != nil {
+			continue
+		}
+
+		fp, err := fingerprintDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("fingerprinting exercise '%s': %w", path, err)
+		}
+
+		scores, confidence := scoreFingerprints(playground, fp)
+		matches = append(matches, ExerciseMatch{
+			Exercise:   exercise,
+			Confidence: confidence,
+			Scores:     scores,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+
+	return matches, nil
+}
+
+// BestPlaygroundMatch returns the exercise most similar to the current playground,
+// or nil if the workshop has no exercises.
+func (w *Workshop) BestPlaygroundMatch() (*ExerciseMatch, error) {
+	matches, err := w.MatchPlayground()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	return &matches[0], nil
+}
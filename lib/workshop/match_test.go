@@ -0,0 +1,243 @@
+package workshop
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles writes files, keyed by their slash separated path relative to root, creating the
+// directories they are in.
+func writeFiles(tb testing.TB, root string, files map[string]string) {
+	tb.Helper()
+
+	for name, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// matchWorkshopFiles is a workshop with two exercises whose problems and solutions differ in their
+// README.mdx, package.json name and files.
+var matchWorkshopFiles = map[string]string{
+	"package.json":          `{"name":"react-fundamentals"}`,
+	"epicshop/package.json": "{}",
+
+	"exercises/01.basics/01.problem.hello/README.mdx":   "# Hello\n\nRender a greeting.\n\nUse a function component.\n",
+	"exercises/01.basics/01.problem.hello/package.json": `{"name":"exercises__sep__01.basics__sep__01.problem.hello"}`,
+	"exercises/01.basics/01.problem.hello/index.tsx":    "export {}\n",
+
+	"exercises/01.basics/01.solution.hello/README.mdx":   "# Hello\n\nThe greeting is rendered.\n",
+	"exercises/01.basics/01.solution.hello/package.json": `{"name":"exercises__sep__01.basics__sep__01.solution.hello"}`,
+	"exercises/01.basics/01.solution.hello/index.tsx":    "export {}\n",
+
+	"exercises/01.basics/02.problem.state/README.mdx":   "# State\n\nCount the clicks.\n\nUse useState.\n\nShow the count.\n",
+	"exercises/01.basics/02.problem.state/package.json": `{"name":"exercises__sep__01.basics__sep__02.problem.state"}`,
+	"exercises/01.basics/02.problem.state/index.tsx":    "export {}\n",
+	"exercises/01.basics/02.problem.state/counter.tsx":  "export {}\n",
+
+	"exercises/01.basics/02.solution.state/README.mdx":   "# State\n\nThe clicks are counted.\n",
+	"exercises/01.basics/02.solution.state/package.json": `{"name":"exercises__sep__01.basics__sep__02.solution.state"}`,
+	"exercises/01.basics/02.solution.state/index.tsx":    "export {}\n",
+	"exercises/01.basics/02.solution.state/counter.tsx":  "export {}\n",
+}
+
+func TestMatchPlayground(t *testing.T) {
+	tests := []struct {
+		name       string
+		playground map[string]string
+		// wantBest is the folder name of the best match, empty if no exercise should match with confidence.
+		wantBest       string
+		wantConfidence float64
+		wantScores     MatchScores
+	}{
+		{
+			name: "exact",
+			playground: map[string]string{
+				"README.mdx":   matchWorkshopFiles["exercises/01.basics/02.problem.state/README.mdx"],
+				"package.json": matchWorkshopFiles["exercises/01.basics/02.problem.state/package.json"],
+				"index.tsx":    "export {}\n",
+				"counter.tsx":  "export {}\n",
+			},
+			wantBest:       "02.problem.state",
+			wantConfidence: 1,
+			wantScores:     MatchScores{Readme: 1, PackageName: 1, FileTree: 1},
+		},
+		{
+			// The user edited a line of the README.mdx and added a file: README.mdx shares 3 of its
+			// 4 lines with the exercise, Dice 2*3/(4+4), and the file tree 4 of 5 files, Jaccard 4/5.
+			name: "partial",
+			playground: map[string]string{
+				"README.mdx":   "# State\n\nCount the clicks.\n\nUse useReducer.\n\nShow the count.\n",
+				"package.json": matchWorkshopFiles["exercises/01.basics/02.problem.state/package.json"],
+				"index.tsx":    "export {}\n",
+				"counter.tsx":  "export { Counter }\n",
+				"notes.md":     "todo\n",
+			},
+			wantBest:       "02.problem.state",
+			wantConfidence: 0.75*readmeWeight + 1*packageNameWeight + 0.8*fileTreeWeight,
+			wantScores:     MatchScores{Readme: 0.75, PackageName: 1, FileTree: 0.8},
+		},
+		{
+			name: "dependencies and caches are ignored",
+			playground: map[string]string{
+				"README.mdx":                     matchWorkshopFiles["exercises/01.basics/01.problem.hello/README.mdx"],
+				"package.json":                   matchWorkshopFiles["exercises/01.basics/01.problem.hello/package.json"],
+				"index.tsx":                      "export {}\n",
+				"node_modules/react/index.js":    "module.exports = {}\n",
+				"node_modules/.vite/deps/_.json": "{}",
+			},
+			wantBest:       "01.problem.hello",
+			wantConfidence: 1,
+			wantScores:     MatchScores{Readme: 1, PackageName: 1, FileTree: 1},
+		},
+		{
+			name: "no match",
+			playground: map[string]string{
+				"go.mod":  "module example.com/other\n",
+				"main.go": "package main\n",
+			},
+			wantConfidence: 0,
+			wantScores:     MatchScores{Readme: 0, PackageName: 0, FileTree: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, matchWorkshopFiles)
+			writeFiles(t, filepath.Join(root, "playground"), tt.playground)
+
+			w, err := WorkshopFromPath(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			matches, err := w.MatchPlayground()
+			if err != nil {
+				t.Fatalf("MatchPlayground() returned error: %v", err)
+			}
+			if len(matches) != 4 {
+				t.Fatalf("MatchPlayground() returned %d matches, want one for each of the 4 exercise folders", len(matches))
+			}
+
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Confidence > matches[i-1].Confidence {
+					t.Errorf("matches are not sorted by decreasing confidence: %v after %v", matches[i].Confidence, matches[i-1].Confidence)
+				}
+			}
+
+			best := matches[0]
+			if tt.wantBest != "" && filepath.Base(best.Exercise.Path()) != tt.wantBest {
+				t.Errorf("best match = %s, want %s", filepath.Base(best.Exercise.Path()), tt.wantBest)
+			}
+			if !approxEqual(best.Confidence, tt.wantConfidence) {
+				t.Errorf("best match confidence = %v, want %v", best.Confidence, tt.wantConfidence)
+			}
+			if !approxEqual(best.Scores.Readme, tt.wantScores.Readme) ||
+				!approxEqual(best.Scores.PackageName, tt.wantScores.PackageName) ||
+				!approxEqual(best.Scores.FileTree, tt.wantScores.FileTree) {
+				t.Errorf("best match scores = %+v, want %+v", best.Scores, tt.wantScores)
+			}
+		})
+	}
+}
+
+func TestMatchPlaygroundWithoutSignals(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"package.json":          "{}",
+		"epicshop/package.json": "{}",
+	})
+	for _, dir := range []string{"exercises/01.basics/01.problem.hello", "playground"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := WorkshopFromPath(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	best, err := w.BestPlaygroundMatch()
+	if err != nil {
+		t.Fatalf("BestPlaygroundMatch() returned error: %v", err)
+	}
+	if best == nil {
+		t.Fatal("BestPlaygroundMatch() = nil, want the only exercise")
+	}
+	if best.Confidence != 0 {
+		t.Errorf("confidence = %v, want 0 when no signal is available", best.Confidence)
+	}
+	if best.Scores != (MatchScores{Readme: -1, PackageName: -1, FileTree: -1}) {
+		t.Errorf("scores = %+v, want all signals unavailable", best.Scores)
+	}
+	if got := best.Explain(); got != "" {
+		t.Errorf("Explain() = %q, want empty", got)
+	}
+}
+
+func TestLinesSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{name: "both empty", want: 1},
+		{name: "one empty", a: []string{"a"}, want: 0},
+		{name: "equal", a: []string{"a", "b"}, b: []string{"b", "a"}, want: 1},
+		{name: "disjoint", a: []string{"a"}, b: []string{"b"}, want: 0},
+		{name: "partial", a: []string{"a", "b", "c"}, b: []string{"a", "b"}, want: 0.8},
+		// Repeated lines only match as many times as they appear on both sides
+		{name: "repeated lines", a: []string{"a", "a", "a"}, b: []string{"a"}, want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := linesSimilarity(tt.a, tt.b); !approxEqual(got, tt.want) {
+				t.Errorf("linesSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSimilarity(t *testing.T) {
+	set := func(keys ...string) map[string]bool {
+		s := make(map[string]bool)
+		for _, k := range keys {
+			s[k] = true
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		a, b map[string]bool
+		want float64
+	}{
+		{name: "both empty", a: set(), b: set(), want: 1},
+		{name: "one empty", a: set("a"), b: set(), want: 0},
+		{name: "equal", a: set("a", "b"), b: set("b", "a"), want: 1},
+		{name: "disjoint", a: set("a"), b: set("b"), want: 0},
+		{name: "partial", a: set("a", "b", "c"), b: set("b", "c", "d"), want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setSimilarity(tt.a, tt.b); !approxEqual(got, tt.want) {
+				t.Errorf("setSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,60 @@
+package workshop
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TitleFromMDX returns the title of an MDX document, taken from the title field of its frontmatter
+// or, if there isn't one, from its first heading. Returns an empty string if the document has neither.
+func TitleFromMDX(mdxPath string) (string, error) {
+	file, err := os.Open(mdxPath)
+	if err != nil {
+		return "", fmt.Errorf("opening '%s' file: %w", mdxPath, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	inFrontmatter := false
+	firstLine := true
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if firstLine {
+			firstLine = false
+			if line == "---" {
+				inFrontmatter = true
+				continue
+			}
+		}
+
+		if inFrontmatter {
+			if line == "---" {
+				inFrontmatter = false
+				continue
+			}
+			if value, ok := strings.CutPrefix(line, "title:"); ok {
+				return cleanTitle(strings.Trim(strings.TrimSpace(value), `"'`)), nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			return cleanTitle(strings.TrimLeft(line, "#")), nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading '%s' file: %w", mdxPath, err)
+	}
+
+	return "", nil
+}
+
+func cleanTitle(title string) string {
+	title = strings.ReplaceAll(title, "`", "")
+	return strings.TrimSpace(title)
+}
@@ -0,0 +1,153 @@
+package workshop
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Epicshop directory names follow this grammar:
+//
+//	section  = number "." slug
+//	exercise = number "." state "." slug
+//	number   = digit { digit }
+//	state    = "problem" | "solution"
+//	slug     = slugchar { slugchar | "." }
+//	slugchar = letter | digit | "-" | "_"
+//
+// Slugs can contain dots, but can't start or end with one.
+
+var (
+	ErrMissingNumber = errors.New("missing number")
+	ErrInvalidNumber = errors.New("invalid number")
+	ErrMissingState  = errors.New("missing problem/solution state")
+	ErrInvalidState  = errors.New("invalid problem/solution state")
+	ErrMissingSlug   = errors.New("missing slug")
+	ErrInvalidSlug   = errors.New("invalid slug")
+)
+
+// NameKind is the kind of directory name being parsed.
+type NameKind string
+
+const (
+	SectionName  NameKind = "section"
+	ExerciseName NameKind = "exercise"
+)
+
+// NameError is returned when a directory name doesn't follow the epicshop naming grammar.
+// The underlying error is one of the Err* errors of this package, and can be checked with errors.Is.
+type NameError struct {
+	Kind NameKind
+	Name string
+	Part string
+	Err  error
+}
+
+func (e *NameError) Error() string {
+	if e.Part == "" {
+		return fmt.Sprintf("%s name '%s': %v", e.Kind, e.Name, e.Err)
+	}
+	return fmt.Sprintf("%s name '%s': %v '%s'", e.Kind, e.Name, e.Err, e.Part)
+}
+
+func (e *NameError) Unwrap() error {
+	return e.Err
+}
+
+// ParsedSectionName holds the parts of a section directory name, e.g. "01.using-jsx".
+type ParsedSectionName struct {
+	Number int
+	Slug   string
+}
+
+// ParsedExerciseName holds the parts of an exercise directory name, e.g. "02.problem.nesting".
+type ParsedExerciseName struct {
+	Number int
+	State  ExerciseState
+	Slug   string
+}
+
+func ParseSectionName(name string) (*ParsedSectionName, error) {
+	numberPart, slug, found := strings.Cut(name, ".")
+
+	number, err := parseNumber(numberPart)
+	if err != nil {
+		return nil, &NameError{Kind: SectionName, Name: name, Part: numberPart, Err: err}
+	}
+
+	if !found || slug == "" {
+		return nil, &NameError{Kind: SectionName, Name: name, Err: ErrMissingSlug}
+	}
+
+	if !isValidSlug(slug) {
+		return nil, &NameError{Kind: SectionName, Name: name, Part: slug, Err: ErrInvalidSlug}
+	}
+
+	return &ParsedSectionName{Number: number, Slug: slug}, nil
+}
+
+func ParseExerciseName(name string) (*ParsedExerciseName, error) {
+	numberPart, rest, found := strings.Cut(name, ".")
+
+	number, err := parseNumber(numberPart)
+	if err != nil {
+		return nil, &NameError{Kind: ExerciseName, Name: name, Part: numberPart, Err: err}
+	}
+
+	if !found || rest == "" {
+		return nil, &NameError{Kind: ExerciseName, Name: name, Err: ErrMissingState}
+	}
+
+	statePart, slug, found := strings.Cut(rest, ".")
+	state := ExerciseState(statePart)
+	if state != ProblemState && state != SolutionState {
+		return nil, &NameError{Kind: ExerciseName, Name: name, Part: statePart, Err: ErrInvalidState}
+	}
+
+	if !found || slug == "" {
+		return nil, &NameError{Kind: ExerciseName, Name: name, Err: ErrMissingSlug}
+	}
+
+	if !isValidSlug(slug) {
+		return nil, &NameError{Kind: ExerciseName, Name: name, Part: slug, Err: ErrInvalidSlug}
+	}
+
+	return &ParsedExerciseName{Number: number, State: state, Slug: slug}, nil
+}
+
+func parseNumber(s string) (int, error) {
+	if s == "" {
+		return 0, ErrMissingNumber
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, ErrInvalidNumber
+		}
+	}
+
+	number, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidNumber
+	}
+
+	return number, nil
+}
+
+func isValidSlug(slug string) bool {
+	if strings.HasPrefix(slug, ".") || strings.HasSuffix(slug, ".") {
+		return false
+	}
+
+	for _, r := range slug {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,152 @@
+package workshop
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSectionName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ParsedSectionName
+		wantErr error
+		part    string
+	}{
+		// Names of real epicshop workshops
+		{name: "01.create-dom-elements", want: ParsedSectionName{Number: 1, Slug: "create-dom-elements"}},
+		{name: "03.using-jsx", want: ParsedSectionName{Number: 3, Slug: "using-jsx"}},
+		{name: "05.forms", want: ParsedSectionName{Number: 5, Slug: "forms"}},
+		{name: "10.use-sync-external-store", want: ParsedSectionName{Number: 10, Slug: "use-sync-external-store"}},
+		{name: "007.leading_zeros", want: ParsedSectionName{Number: 7, Slug: "leading_zeros"}},
+
+		// Slugs with dots
+		{name: "04.next.js", want: ParsedSectionName{Number: 4, Slug: "next.js"}},
+		{name: "02.v1.2.3", want: ParsedSectionName{Number: 2, Slug: "v1.2.3"}},
+
+		{name: "", wantErr: ErrMissingNumber},
+		{name: ".using-jsx", wantErr: ErrMissingNumber},
+		{name: "intro", wantErr: ErrInvalidNumber, part: "intro"},
+		{name: "x1.using-jsx", wantErr: ErrInvalidNumber, part: "x1"},
+		{name: "-1.using-jsx", wantErr: ErrInvalidNumber, part: "-1"},
+		{name: "99999999999999999999.using-jsx", wantErr: ErrInvalidNumber, part: "99999999999999999999"},
+		{name: "01", wantErr: ErrMissingSlug},
+		{name: "01.", wantErr: ErrMissingSlug},
+		{name: "01.using jsx", wantErr: ErrInvalidSlug, part: "using jsx"},
+		{name: "01..using-jsx", wantErr: ErrInvalidSlug, part: ".using-jsx"},
+		{name: "01.using-jsx.", wantErr: ErrInvalidSlug, part: "using-jsx."},
+		{name: "01.usíng-jsx", wantErr: ErrInvalidSlug, part: "usíng-jsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSectionName(tt.name)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ParseSectionName(%q) returned error: %v", tt.name, err)
+				}
+				if *got != tt.want {
+					t.Errorf("ParseSectionName(%q) = %+v, want %+v", tt.name, *got, tt.want)
+				}
+				return
+			}
+
+			checkNameError(t, err, SectionName, tt.name, tt.part, tt.wantErr)
+		})
+	}
+}
+
+func TestParseExerciseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ParsedExerciseName
+		wantErr error
+		part    string
+	}{
+		// Names of real epicshop workshops
+		{name: "01.problem.hello", want: ParsedExerciseName{Number: 1, State: ProblemState, Slug: "hello"}},
+		{name: "01.solution.hello", want: ParsedExerciseName{Number: 1, State: SolutionState, Slug: "hello"}},
+		{name: "02.problem.generate-root", want: ParsedExerciseName{Number: 2, State: ProblemState, Slug: "generate-root"}},
+		{name: "03.solution.use-state", want: ParsedExerciseName{Number: 3, State: SolutionState, Slug: "use-state"}},
+		{name: "12.problem.error-boundaries", want: ParsedExerciseName{Number: 12, State: ProblemState, Slug: "error-boundaries"}},
+
+		// Slugs with dots
+		{name: "04.problem.next.js", want: ParsedExerciseName{Number: 4, State: ProblemState, Slug: "next.js"}},
+		{name: "01.solution.a.b.c", want: ParsedExerciseName{Number: 1, State: SolutionState, Slug: "a.b.c"}},
+
+		{name: "", wantErr: ErrMissingNumber},
+		{name: ".problem.hello", wantErr: ErrMissingNumber},
+		{name: "README.mdx", wantErr: ErrInvalidNumber, part: "README"},
+		{name: "x1.problem.hello", wantErr: ErrInvalidNumber, part: "x1"},
+		{name: "01", wantErr: ErrMissingState},
+		{name: "01.", wantErr: ErrMissingState},
+		{name: "01.final.hello", wantErr: ErrInvalidState, part: "final"},
+		{name: "01.Problem.hello", wantErr: ErrInvalidState, part: "Problem"},
+		{name: "01.hello", wantErr: ErrInvalidState, part: "hello"},
+		{name: "01.problem", wantErr: ErrMissingSlug},
+		{name: "01.solution.", wantErr: ErrMissingSlug},
+		{name: "01.problem.hello!", wantErr: ErrInvalidSlug, part: "hello!"},
+		{name: "01.problem..hello", wantErr: ErrInvalidSlug, part: ".hello"},
+		{name: "01.problem.hello.", wantErr: ErrInvalidSlug, part: "hello."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExerciseName(tt.name)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ParseExerciseName(%q) returned error: %v", tt.name, err)
+				}
+				if *got != tt.want {
+					t.Errorf("ParseExerciseName(%q) = %+v, want %+v", tt.name, *got, tt.want)
+				}
+				return
+			}
+
+			checkNameError(t, err, ExerciseName, tt.name, tt.part, tt.wantErr)
+		})
+	}
+}
+
+func TestNameErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *NameError
+		want string
+	}{
+		{
+			err:  &NameError{Kind: SectionName, Name: "01", Err: ErrMissingSlug},
+			want: "section name '01': missing slug",
+		},
+		{
+			err:  &NameError{Kind: ExerciseName, Name: "01.final.hello", Part: "final", Err: ErrInvalidState},
+			want: "exercise name '01.final.hello': invalid problem/solution state 'final'",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func checkNameError(t *testing.T, err error, kind NameKind, name string, part string, wantErr error) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("parsing %s name %q succeeded, want error %v", kind, name, wantErr)
+	}
+	if !errors.Is(err, wantErr) {
+		t.Errorf("parsing %s name %q returned error %v, want %v", kind, name, err, wantErr)
+	}
+
+	var nameErr *NameError
+	if !errors.As(err, &nameErr) {
+		t.Fatalf("parsing %s name %q returned error of type %T, want *NameError", kind, name, err)
+	}
+	if nameErr.Kind != kind || nameErr.Name != name || nameErr.Part != part {
+		t.Errorf("NameError = {Kind: %q, Name: %q, Part: %q}, want {Kind: %q, Name: %q, Part: %q}",
+			nameErr.Kind, nameErr.Name, nameErr.Part, kind, name, part)
+	}
+}
@@ -0,0 +1,228 @@
+package workshop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Outline is the full model of a workshop: its sections, in order, and the exercises of each section
+// with their problem and solution directories.
+type Outline struct {
+	Sections  []*OutlineSection
+	exercises []*OutlineExercise
+}
+
+type OutlineSection struct {
+	Section
+	// IntroPath is the path of the README.mdx introducing the section, empty if there is none.
+	IntroPath string
+	// FinishedPath is the path of the FINISHED.mdx shown when the section is completed, empty if there is none.
+	FinishedPath string
+	Exercises    []*OutlineExercise
+}
+
+type OutlineExercise struct {
+	// Exercise is the problem of the exercise, or the solution if the exercise has no problem directory.
+	*Exercise
+	ProblemPath  string
+	SolutionPath string
+	section      *OutlineSection
+	position     int
+}
+
+func (e *OutlineExercise) OutlineSection() *OutlineSection {
+	return e.section
+}
+
+// Solution returns the exercise in its solution state, or nil if the exercise has no solution directory.
+func (e *OutlineExercise) Solution() *Exercise {
+	if e.SolutionPath == "" {
+		return nil
+	}
+
+	solution := *e.Exercise
+	solution.State = SolutionState
+	solution.path = e.SolutionPath
+	return &solution
+}
+
+// LoadOutline reads the exercises directory of the workshop. Directories that don't follow the
+// epicshop naming conventions are ignored.
+func LoadOutline(w *Workshop) (*Outline, error) {
+	exercisesPath := filepath.Join(w.Path, "exercises")
+
+	sectionEntries, err := os.ReadDir(exercisesPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading exercises directory '%s': %w", exercisesPath, err)
+	}
+
+	outline := &Outline{}
+
+	for _, sectionEntry := range sectionEntries {
+		if !sectionEntry.IsDir() {
+			continue
+		}
+
+		parsedSection, err := ParseSectionName(sectionEntry.Name())
+		if err != nil {
+			continue
+		}
+
+		sectionPath := filepath.Join(exercisesPath, sectionEntry.Name())
+		section := &OutlineSection{
+			Section: Section{
+				Number: parsedSection.Number,
+				Slug:   parsedSection.Slug,
+				path:   sectionPath,
+			},
+			IntroPath:    existingFile(filepath.Join(sectionPath, "README.mdx")),
+			FinishedPath: existingFile(filepath.Join(sectionPath, "FINISHED.mdx")),
+		}
+
+		err = loadOutlineExercises(section)
+		if err != nil {
+			return nil, err
+		}
+
+		outline.Sections = append(outline.Sections, section)
+	}
+
+	sort.SliceStable(outline.Sections, func(i, j int) bool {
+		return outline.Sections[i].Number < outline.Sections[j].Number
+	})
+
+	for _, section := range outline.Sections {
+		for _, exercise := range section.Exercises {
+			exercise.position = len(outline.exercises)
+			outline.exercises = append(outline.exercises, exercise)
+		}
+	}
+
+	return outline, nil
+}
+
+func loadOutlineExercises(section *OutlineSection) error {
+	entries, err := os.ReadDir(section.path)
+	if err != nil {
+		return fmt.Errorf("reading section directory '%s': %w", section.path, err)
+	}
+
+	byNumber := make(map[int]*OutlineExercise)
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		parsed, err := ParseExerciseName(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		exercisePath := filepath.Join(section.path, entry.Name())
+		exercise, ok := byNumber[parsed.Number]
+		if !ok {
+			exercise = &OutlineExercise{
+				Exercise: &Exercise{
+					Number:  parsed.Number,
+					Slug:    parsed.Slug,
+					Section: section.Section,
+					State:   parsed.State,
+					path:    exercisePath,
+				},
+				section: section,
+			}
+			byNumber[parsed.Number] = exercise
+			section.Exercises = append(section.Exercises, exercise)
+		}
+
+		switch parsed.State {
+		case ProblemState:
+			exercise.ProblemPath = exercisePath
+			exercise.Slug = parsed.Slug
+			exercise.State = ProblemState
+			exercise.path = exercisePath
+		case SolutionState:
+			exercise.SolutionPath = exercisePath
+		}
+	}
+
+	sort.SliceStable(section.Exercises, func(i, j int) bool {
+		return section.Exercises[i].Number < section.Exercises[j].Number
+	})
+
+	return nil
+}
+
+func existingFile(path string) string {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// Exercises returns all the exercises of the workshop, ordered by section and exercise number.
+func (o *Outline) Exercises() []*OutlineExercise {
+	return o.exercises
+}
+
+// Section looks up a section by its number (e.g. "1" or "01") or by its slug.
+func (o *Outline) Section(ref string) *OutlineSection {
+	number, numErr := strconv.Atoi(ref)
+	for _, section := range o.Sections {
+		if (numErr == nil && section.Number == number) || section.Slug == ref {
+			return section
+		}
+	}
+	return nil
+}
+
+// Exercise looks up an exercise by the number or slug of its section and its own number or slug.
+func (o *Outline) Exercise(sectionRef string, exerciseRef string) *OutlineExercise {
+	section := o.Section(sectionRef)
+	if section == nil {
+		return nil
+	}
+
+	number, numErr := strconv.Atoi(exerciseRef)
+	for _, exercise := range section.Exercises {
+		if (numErr == nil && exercise.Number == number) || exercise.Slug == exerciseRef {
+			return exercise
+		}
+	}
+	return nil
+}
+
+// Find returns the outline entry of an exercise, matching it by section and exercise number.
+func (o *Outline) Find(exercise *Exercise) *OutlineExercise {
+	if exercise == nil {
+		return nil
+	}
+
+	for _, candidate := range o.exercises {
+		if candidate.Section.Number == exercise.Section.Number && candidate.Number == exercise.Number {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// Next returns the exercise after the given one in the workshop, or nil if it is the last one.
+func (o *Outline) Next(exercise *OutlineExercise) *OutlineExercise {
+	if exercise == nil || exercise.position+1 >= len(o.exercises) {
+		return nil
+	}
+	return o.exercises[exercise.position+1]
+}
+
+// Previous returns the exercise before the given one in the workshop, or nil if it is the first one.
+func (o *Outline) Previous(exercise *OutlineExercise) *OutlineExercise {
+	if exercise == nil || exercise.position == 0 {
+		return nil
+	}
+	return o.exercises[exercise.position-1]
+}
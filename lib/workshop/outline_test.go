@@ -0,0 +1,312 @@
+package workshop
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// outlineWorkshopFiles is a workshop whose sections and exercises cover the layouts LoadOutline handles:
+// sections with and without intro and FINISHED.mdx, exercises without a problem or a solution, numbers
+// that don't sort as strings, and folders that don't follow the naming conventions.
+var outlineWorkshopFiles = map[string]string{
+	"package.json":          `{"name":"react-hooks"}`,
+	"epicshop/package.json": "{}",
+
+	"exercises/README.mdx":   "# React Hooks\n",
+	"exercises/FINISHED.mdx": "# Done\n",
+
+	"exercises/1.basics/README.mdx":                   "# Basics\n",
+	"exercises/1.basics/FINISHED.mdx":                 "# Basics done\n",
+	"exercises/1.basics/01.problem.state/README.mdx":  "# State\n",
+	"exercises/1.basics/01.solution.state/README.mdx": "# State\n",
+	// Only a solution, e.g. a walkthrough
+	"exercises/1.basics/02.solution.walkthrough/README.mdx": "# Walkthrough\n",
+	// Only a problem, e.g. a bonus exercise
+	"exercises/1.basics/03.problem.bonus/README.mdx": "# Bonus\n",
+	"exercises/1.basics/notes/README.mdx":            "# Not an exercise\n",
+	"exercises/1.basics/04.draft.idea/README.mdx":    "# Not an exercise\n",
+
+	// No intro nor FINISHED.mdx, and README.mdx is a folder
+	"exercises/2.effects/01.problem.effect/README.mdx":  "# Effect\n",
+	"exercises/2.effects/01.solution.effect/README.mdx": "# Effect\n",
+	"exercises/2.effects/FINISHED.mdx/.keep":            "",
+
+	"exercises/10.extra/01.problem.context/README.mdx":  "# Context\n",
+	"exercises/10.extra/01.solution.context/README.mdx": "# Context\n",
+
+	"exercises/drafts/01.problem.draft/README.mdx": "# Not a section\n",
+	"exercises/2/01.problem.draft/README.mdx":      "# Not a section\n",
+}
+
+func loadTestOutline(t *testing.T) (*Outline, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	writeFiles(t, root, outlineWorkshopFiles)
+
+	w, err := WorkshopFromPath(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := LoadOutline(w)
+	if err != nil {
+		t.Fatalf("LoadOutline() returned error: %v", err)
+	}
+
+	return outline, filepath.Join(root, "exercises")
+}
+
+// descriptor identifies an outline exercise by the folders of its section and exercise, e.g.
+// "1.basics/01.problem.state", or "<nil>".
+func descriptor(exercise *OutlineExercise) string {
+	if exercise == nil {
+		return "<nil>"
+	}
+	return filepath.Base(exercise.Section.Path()) + "/" + filepath.Base(exercise.Path())
+}
+
+func TestLoadOutline(t *testing.T) {
+	outline, exercisesPath := loadTestOutline(t)
+
+	tests := []struct {
+		section      string
+		intro        string
+		finished     string
+		exercises    []string
+		problemPaths []string
+		solutions    []string
+	}{
+		{
+			section:      "1.basics",
+			intro:        "1.basics/README.mdx",
+			finished:     "1.basics/FINISHED.mdx",
+			exercises:    []string{"01.problem.state", "02.solution.walkthrough", "03.problem.bonus"},
+			problemPaths: []string{"01.problem.state", "", "03.problem.bonus"},
+			solutions:    []string{"01.solution.state", "02.solution.walkthrough", ""},
+		},
+		{
+			section:      "2.effects",
+			exercises:    []string{"01.problem.effect"},
+			problemPaths: []string{"01.problem.effect"},
+			solutions:    []string{"01.solution.effect"},
+		},
+		{
+			section:      "10.extra",
+			exercises:    []string{"01.problem.context"},
+			problemPaths: []string{"01.problem.context"},
+			solutions:    []string{"01.solution.context"},
+		},
+	}
+
+	if len(outline.Sections) != len(tests) {
+		t.Fatalf("LoadOutline() returned %d sections, want %d", len(outline.Sections), len(tests))
+	}
+
+	rel := func(base string, path string) string {
+		if path == "" {
+			return ""
+		}
+		r, err := filepath.Rel(base, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return filepath.ToSlash(r)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.section, func(t *testing.T) {
+			section := outline.Sections[i]
+			if got := filepath.Base(section.Path()); got != tt.section {
+				t.Fatalf("section %d = %s, want %s", i, got, tt.section)
+			}
+			if got := rel(exercisesPath, section.IntroPath); got != tt.intro {
+				t.Errorf("IntroPath = %q, want %q", got, tt.intro)
+			}
+			if got := rel(exercisesPath, section.FinishedPath); got != tt.finished {
+				t.Errorf("FinishedPath = %q, want %q", got, tt.finished)
+			}
+
+			if len(section.Exercises) != len(tt.exercises) {
+				t.Fatalf("section has %d exercises, want %d", len(section.Exercises), len(tt.exercises))
+			}
+			for j, exercise := range section.Exercises {
+				if got := filepath.Base(exercise.Path()); got != tt.exercises[j] {
+					t.Errorf("exercise %d = %s, want %s", j, got, tt.exercises[j])
+				}
+				if got := rel(section.Path(), exercise.ProblemPath); got != tt.problemPaths[j] {
+					t.Errorf("exercise %d ProblemPath = %q, want %q", j, got, tt.problemPaths[j])
+				}
+				if got := rel(section.Path(), exercise.SolutionPath); got != tt.solutions[j] {
+					t.Errorf("exercise %d SolutionPath = %q, want %q", j, got, tt.solutions[j])
+				}
+				if exercise.OutlineSection() != section {
+					t.Errorf("exercise %d OutlineSection() is not its section", j)
+				}
+			}
+		})
+	}
+}
+
+func TestOutlineSolution(t *testing.T) {
+	outline, _ := loadTestOutline(t)
+
+	tests := []struct {
+		section, exercise string
+		want              string
+	}{
+		{section: "1", exercise: "1", want: "01.solution.state"},
+		{section: "1", exercise: "2", want: "02.solution.walkthrough"},
+		{section: "1", exercise: "3", want: ""},
+	}
+
+	for _, tt := range tests {
+		exercise := outline.Exercise(tt.section, tt.exercise)
+		if exercise == nil {
+			t.Fatalf("Exercise(%q, %q) = nil", tt.section, tt.exercise)
+		}
+
+		solution := exercise.Solution()
+		if tt.want == "" {
+			if solution != nil {
+				t.Errorf("Exercise(%q, %q).Solution() = %s, want nil", tt.section, tt.exercise, solution.Path())
+			}
+			continue
+		}
+		if solution == nil {
+			t.Fatalf("Exercise(%q, %q).Solution() = nil, want %s", tt.section, tt.exercise, tt.want)
+		}
+		if got := filepath.Base(solution.Path()); got != tt.want || !solution.IsSolution() {
+			t.Errorf("Exercise(%q, %q).Solution() = %s (solution: %v), want %s", tt.section, tt.exercise, got, solution.IsSolution(), tt.want)
+		}
+	}
+}
+
+func TestOutlineLookup(t *testing.T) {
+	outline, _ := loadTestOutline(t)
+
+	sections := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "1", want: "1.basics"},
+		{ref: "01", want: "1.basics"},
+		{ref: "basics", want: "1.basics"},
+		{ref: "10", want: "10.extra"},
+		{ref: "extra", want: "10.extra"},
+		{ref: "3", want: ""},
+		{ref: "drafts", want: ""},
+		{ref: "1.basics", want: ""},
+		{ref: "", want: ""},
+	}
+
+	for _, tt := range sections {
+		t.Run("section "+tt.ref, func(t *testing.T) {
+			section := outline.Section(tt.ref)
+			got := ""
+			if section != nil {
+				got = filepath.Base(section.Path())
+			}
+			if got != tt.want {
+				t.Errorf("Section(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+
+	exercises := []struct {
+		section, exercise string
+		want              string
+	}{
+		{section: "1", exercise: "1", want: "1.basics/01.problem.state"},
+		{section: "basics", exercise: "state", want: "1.basics/01.problem.state"},
+		{section: "01", exercise: "02", want: "1.basics/02.solution.walkthrough"},
+		{section: "1", exercise: "walkthrough", want: "1.basics/02.solution.walkthrough"},
+		{section: "1", exercise: "bonus", want: "1.basics/03.problem.bonus"},
+		{section: "extra", exercise: "1", want: "10.extra/01.problem.context"},
+		{section: "1", exercise: "4", want: "<nil>"},
+		{section: "1", exercise: "idea", want: "<nil>"},
+		{section: "2", exercise: "state", want: "<nil>"},
+		{section: "3", exercise: "1", want: "<nil>"},
+	}
+
+	for _, tt := range exercises {
+		t.Run("exercise "+tt.section+" "+tt.exercise, func(t *testing.T) {
+			if got := descriptor(outline.Exercise(tt.section, tt.exercise)); got != tt.want {
+				t.Errorf("Exercise(%q, %q) = %s, want %s", tt.section, tt.exercise, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineNavigation(t *testing.T) {
+	outline, _ := loadTestOutline(t)
+
+	order := []string{
+		"1.basics/01.problem.state",
+		"1.basics/02.solution.walkthrough",
+		"1.basics/03.problem.bonus",
+		"2.effects/01.problem.effect",
+		"10.extra/01.problem.context",
+	}
+
+	exercises := outline.Exercises()
+	if len(exercises) != len(order) {
+		t.Fatalf("Exercises() returned %d exercises, want %d", len(exercises), len(order))
+	}
+
+	for i, exercise := range exercises {
+		t.Run(order[i], func(t *testing.T) {
+			if got := descriptor(exercise); got != order[i] {
+				t.Fatalf("exercise %d = %s, want %s", i, got, order[i])
+			}
+
+			wantNext, wantPrevious := "<nil>", "<nil>"
+			if i+1 < len(order) {
+				wantNext = order[i+1]
+			}
+			if i > 0 {
+				wantPrevious = order[i-1]
+			}
+
+			if got := descriptor(outline.Next(exercise)); got != wantNext {
+				t.Errorf("Next() = %s, want %s", got, wantNext)
+			}
+			if got := descriptor(outline.Previous(exercise)); got != wantPrevious {
+				t.Errorf("Previous() = %s, want %s", got, wantPrevious)
+			}
+		})
+	}
+
+	if got := outline.Next(nil); got != nil {
+		t.Errorf("Next(nil) = %s, want nil", descriptor(got))
+	}
+	if got := outline.Previous(nil); got != nil {
+		t.Errorf("Previous(nil) = %s, want nil", descriptor(got))
+	}
+}
+
+func TestOutlineFind(t *testing.T) {
+	outline, _ := loadTestOutline(t)
+
+	tests := []struct {
+		name     string
+		exercise *Exercise
+		want     string
+	}{
+		{name: "problem", exercise: outline.Exercise("1", "1").Exercise, want: "1.basics/01.problem.state"},
+		{name: "solution", exercise: outline.Exercise("2", "1").Solution(), want: "2.effects/01.problem.effect"},
+		{name: "solution only", exercise: outline.Exercise("1", "2").Solution(), want: "1.basics/02.solution.walkthrough"},
+		// Exercises are found by number, so a renamed exercise is still found
+		{name: "renamed", exercise: &Exercise{Number: 1, Slug: "other", Section: Section{Number: 10}}, want: "10.extra/01.problem.context"},
+		{name: "unknown", exercise: &Exercise{Number: 9, Section: Section{Number: 1}}, want: "<nil>"},
+		{name: "nil", want: "<nil>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := descriptor(outline.Find(tt.exercise)); got != tt.want {
+				t.Errorf("Find() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -50,3 +50,19 @@ func LoadPackageConfig(workshopPath string) (*PackageConfig, error) {
 
 	return &config, nil
 }
+
+// Validate checks the fields kody relies on and returns a warning for each one that is missing. A missing
+// epicshop.product.slug is reported by Workshop.Warnings, since the slug has fallbacks.
+func (c *PackageConfig) Validate() []string {
+	var warnings []string
+
+	if c.Name == "" {
+		warnings = append(warnings, "package.json has no name")
+	}
+
+	if c.Epicshop.Title == "" {
+		warnings = append(warnings, "package.json has no epicshop.title")
+	}
+
+	return warnings
+}
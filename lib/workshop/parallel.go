@@ -0,0 +1,35 @@
+package workshop
+
+import (
+	"runtime"
+	"sync"
+)
+
+// parallelFor calls fn for every index in [0, n) using a pool of GOMAXPROCS workers, so it runs
+// sequentially with GOMAXPROCS=1. Callers are responsible for making fn safe to run concurrently.
+func parallelFor(n int, fn func(i int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range n {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+}
@@ -1,8 +1,11 @@
 package workshop
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 )
 
 func CopyExercise(playgroundPath string, outputDir string) error {
@@ -19,3 +22,41 @@ func CopyExercise(playgroundPath string, outputDir string) error {
 
 	return nil
 }
+
+// SavedExerciseTime returns the time the exercise was last saved to outputDir, or nil if it was never saved.
+func SavedExerciseTime(outputDir string, w *Workshop, exercise *Exercise) (*time.Time, error) {
+	info, err := os.Stat(DefaultExerciseDir(outputDir, w, exercise))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting info for saved exercise: %w", err)
+	}
+
+	if !info.IsDir() {
+		return nil, nil
+	}
+
+	modTime := info.ModTime()
+	return &modTime, nil
+}
+
+// NotesFileNames are the names of the files, in the saved copy of an exercise, where notes about the exercise can be kept.
+var NotesFileNames = []string{"NOTES.md", "notes.md", "NOTES.mdx", "notes.mdx"}
+
+// SavedExerciseNotes returns the contents of the notes file of the saved copy of the exercise,
+// or an empty string if there are no notes.
+func SavedExerciseNotes(outputDir string, w *Workshop, exercise *Exercise) (string, error) {
+	exerciseDir := DefaultExerciseDir(outputDir, w, exercise)
+	for _, name := range NotesFileNames {
+		data, err := os.ReadFile(filepath.Join(exerciseDir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading notes of saved exercise: %w", err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
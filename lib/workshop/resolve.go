@@ -0,0 +1,111 @@
+package workshop
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"os"
+	"path/filepath"
+)
+
+// ResolutionSource tells how the current workshop was chosen.
+type ResolutionSource string
+
+const (
+	FromFlag          ResolutionSource = "--workshop flag"
+	FromWorkingDir    ResolutionSource = "working directory"
+	FromConfig        ResolutionSource = "workshop.dir configuration"
+	FromAutoDetection ResolutionSource = "auto-detection"
+)
+
+// ResolveOptions holds every place the current workshop can come from. They are tried in this order:
+//
+//  1. FlagPath, the workshop passed explicitly with the --workshop flag
+//  2. WorkingDir, if it is inside a workshop
+//  3. ConfigPath, the workshop.dir configuration
+//  4. auto-detection of the most recently active workshop with Search
+type ResolveOptions struct {
+	FlagPath   string
+	WorkingDir string
+	ConfigPath string
+	Search     SearchOptions
+}
+
+// Resolution is the current workshop together with where it came from.
+type Resolution struct {
+	Workshop *Workshop
+	Source   ResolutionSource
+}
+
+var ErrNoWorkshop = errors.New("please provide a path to the workshop folder using the --workshop flag or the workshop.dir configuration, run kody from inside a workshop, or set workshops.dir to auto-detect it")
+
+// ResolveOptionsFromConfig builds the resolve options from the configuration and the current working directory.
+// flagPath is the value of the --workshop flag, or empty if it wasn't passed.
+func ResolveOptionsFromConfig(cfg *config.Config, flagPath string) ResolveOptions {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		workingDir = ""
+	}
+
+	return ResolveOptions{
+		FlagPath:   flagPath,
+		WorkingDir: workingDir,
+		ConfigPath: cfg.GetString("workshop.dir"),
+		Search:     SearchOptionsFromConfig(cfg),
+	}
+}
+
+// Resolve finds the current workshop, see ResolveOptions for the order in which the options are tried.
+func Resolve(opts ResolveOptions) (*Resolution, error) {
+	if opts.FlagPath != "" {
+		return resolveFromPath(opts.FlagPath, FromFlag)
+	}
+
+	if opts.WorkingDir != "" {
+		if workshopPath, ok := FindWorkshopUp(opts.WorkingDir); ok {
+			return resolveFromPath(workshopPath, FromWorkingDir)
+		}
+	}
+
+	if opts.ConfigPath != "" {
+		return resolveFromPath(opts.ConfigPath, FromConfig)
+	}
+
+	if len(opts.Search.Roots) > 0 {
+		w, err := DetectCurrentWorkshopIn(opts.Search)
+		if err != nil {
+			return nil, fmt.Errorf("auto-detecting workshop from workshops directories '%s': %w", opts.Search, err)
+		}
+		return &Resolution{Workshop: w, Source: FromAutoDetection}, nil
+	}
+
+	return nil, ErrNoWorkshop
+}
+
+func resolveFromPath(workshopPath string, source ResolutionSource) (*Resolution, error) {
+	w, err := WorkshopFromPath(workshopPath)
+	if err != nil {
+		return nil, fmt.Errorf("getting workshop from path '%s' (from %s): %w", workshopPath, source, err)
+	}
+	return &Resolution{Workshop: w, Source: source}, nil
+}
+
+// FindWorkshopUp looks for a workshop in dir and each of its parent directories.
+func FindWorkshopUp(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if isWorkshopFolder(dir) {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,123 @@
+package workshop
+
+import (
+	"fmt"
+	"github.com/andrerfcsantos/kody/lib/config"
+	"github.com/andrerfcsantos/kody/lib/directory"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SearchOptions tells where and how deep to look for workshops.
+type SearchOptions struct {
+	// Roots are the directories under which workshops are searched.
+	Roots []string
+	// Depth is how many directory levels below each root are searched. A depth of 1 only looks at
+	// the direct sub-directories of the roots.
+	Depth int
+	// Include are glob patterns a workshop folder name or its slash separated path relative to the
+	// root must match. An empty list includes every workshop.
+	Include []string
+	// Exclude are glob patterns for folder names or relative paths that are skipped while searching.
+	Exclude []string
+}
+
+// SearchOptionsFromConfig builds the search options from the workshops.* configurations.
+func SearchOptionsFromConfig(cfg *config.Config) SearchOptions {
+	var roots []string
+	if dir := cfg.GetString("workshops.dir"); dir != "" {
+		roots = append(roots, dir)
+	}
+	roots = append(roots, cfg.GetStringSlice("workshops.roots")...)
+
+	return SearchOptions{
+		Roots:   roots,
+		Depth:   cfg.GetInt("workshops.depth"),
+		Include: cfg.GetStringSlice("workshops.include"),
+		Exclude: cfg.GetStringSlice("workshops.exclude"),
+	}
+}
+
+func (o SearchOptions) String() string {
+	return strings.Join(o.Roots, ", ")
+}
+
+func matchesAny(patterns []string, name string, relPath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, relPath); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// FindWorkshops returns the paths of all the workshops found under the roots of the search options.
+func FindWorkshops(opts SearchOptions) ([]string, error) {
+	depth := opts.Depth
+	if depth < 1 {
+		depth = 1
+	}
+
+	var workshopPaths []string
+	seen := make(map[string]bool)
+
+	var search func(root string, dir string, level int) error
+	search = func(root string, dir string, level int) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("reading directory '%s': %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			// Hidden folders are never workshops, and searching them can be slow, e.g. ~/.cache
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || IsDependencyOrCacheDir(entry.Name()) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			relPath, err := filepath.Rel(root, path)
+			if err != nil {
+				relPath = entry.Name()
+			}
+			relPath = filepath.ToSlash(relPath)
+
+			if matchesAny(opts.Exclude, entry.Name(), relPath) {
+				continue
+			}
+
+			if isWorkshopFolder(path) {
+				if len(opts.Include) > 0 && !matchesAny(opts.Include, entry.Name(), relPath) {
+					continue
+				}
+				if absPath, err := filepath.Abs(path); err == nil && !seen[absPath] {
+					seen[absPath] = true
+					workshopPaths = append(workshopPaths, path)
+				}
+				continue
+			}
+
+			if level < depth {
+				// Unreadable nested directories are not an error
+				_ = search(root, path, level+1)
+			}
+		}
+
+		return nil
+	}
+
+	for _, root := range opts.Roots {
+		if !directory.Exists(root) {
+			return nil, fmt.Errorf("workshops directory '%s' does not exist", root)
+		}
+
+		if err := search(root, root, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	return workshopPaths, nil
+}
@@ -1,12 +1,30 @@
 package workshop
 
-import "fmt"
+import (
+	"fmt"
+	"path/filepath"
+)
 
 type Section struct {
 	Number int
 	Slug   string
+	path   string
 }
 
 func (s *Section) Descriptor() string {
 	return fmt.Sprintf("%0.2d-%s", s.Number, s.Slug)
 }
+
+func (s *Section) Path() string {
+	return s.path
+}
+
+// Title returns the title of the section from its README.mdx, falling back to the slug.
+func (s *Section) Title() string {
+	if s.path != "" {
+		if title, err := TitleFromMDX(filepath.Join(s.path, "README.mdx")); err == nil && title != "" {
+			return title
+		}
+	}
+	return s.Slug
+}
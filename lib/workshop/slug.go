@@ -0,0 +1,97 @@
+package workshop
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SlugSource tells where the slug of a workshop came from.
+type SlugSource string
+
+const (
+	SlugFromOverride    SlugSource = "config override"
+	SlugFromProduct     SlugSource = "epicshop.product.slug"
+	SlugFromPackageName SlugSource = "package name"
+	SlugFromGithubRepo  SlugSource = "epicshop.githubRepo"
+	SlugFromFolderName  SlugSource = "folder name"
+)
+
+var (
+	slugOverridesMu sync.RWMutex
+	slugOverrides   map[string]string
+)
+
+// SetSlugOverrides sets explicit slugs for workshops, keyed by the workshop folder name or path.
+// Keys are matched case-insensitively.
+func SetSlugOverrides(overrides map[string]string) {
+	slugOverridesMu.Lock()
+	defer slugOverridesMu.Unlock()
+
+	slugOverrides = make(map[string]string, len(overrides))
+	for key, slug := range overrides {
+		slugOverrides[strings.ToLower(filepath.Clean(key))] = slug
+	}
+}
+
+func slugOverride(workshopPath string) string {
+	slugOverridesMu.RLock()
+	defer slugOverridesMu.RUnlock()
+
+	candidates := []string{filepath.Base(workshopPath), filepath.Clean(workshopPath)}
+	if absPath, err := filepath.Abs(workshopPath); err == nil {
+		candidates = append(candidates, absPath)
+	}
+
+	for _, candidate := range candidates {
+		if slug, ok := slugOverrides[strings.ToLower(candidate)]; ok && slug != "" {
+			return slug
+		}
+	}
+
+	return ""
+}
+
+// SlugWithSource resolves the slug of the workshop and tells where it came from. The slug is taken from,
+// in order: a config override, epicshop.product.slug, the package name, epicshop.githubRepo and the folder name.
+func (w *Workshop) SlugWithSource() (string, SlugSource) {
+	if slug := slugOverride(w.Path); slug != "" {
+		return slug, SlugFromOverride
+	}
+
+	if slug := w.config.Epicshop.Product.Slug; slug != "" {
+		return slug, SlugFromProduct
+	}
+
+	if slug := slugify(w.config.Name); slug != "" {
+		return slug, SlugFromPackageName
+	}
+
+	if repo := strings.TrimSuffix(strings.TrimRight(w.config.Epicshop.GithubRepo, "/"), ".git"); repo != "" {
+		if slug := slugify(repo); slug != "" {
+			return slug, SlugFromGithubRepo
+		}
+	}
+
+	return slugify(filepath.Base(w.Path)), SlugFromFolderName
+}
+
+// slugify keeps the last segment of a path-like name (e.g. a scoped package name or a repository URL)
+// and replaces the characters that are not safe in folder names.
+func slugify(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, name)
+
+	return strings.Trim(slug, "-.")
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io/fs"
 	"github.com/andrerfcsantos/kody/lib/directory"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,16 +11,38 @@ import (
 )
 
 type Workshop struct {
-	Path   string
-	config *PackageConfig
+	Path    string
+	config  *PackageConfig
+	index   *HashIndex
+	outline *Outline
 }
 
 func (w *Workshop) Slug() string {
-	return w.config.Epicshop.Product.Slug
+	slug, _ := w.SlugWithSource()
+	return slug
 }
 
+// Warnings returns the problems found in the workshop package.json that can make kody behave unexpectedly.
+func (w *Workshop) Warnings() []string {
+	warnings := w.config.Validate()
+
+	// A missing epicshop.product.slug only matters when no override replaces it
+	if slug, source := w.SlugWithSource(); source != SlugFromProduct && source != SlugFromOverride {
+		warnings = append(warnings, fmt.Sprintf("package.json has no epicshop.product.slug, using '%s' from the %s as the workshop slug, set workshop.slugs.%s in the config to choose another one", slug, source, filepath.Base(w.Path)))
+	}
+
+	return warnings
+}
+
+// Title returns the epicshop title of the workshop, falling back to the product display name and the folder name.
 func (w *Workshop) Title() string {
-	return w.config.Epicshop.Title
+	if w.config.Epicshop.Title != "" {
+		return w.config.Epicshop.Title
+	}
+	if w.config.Epicshop.Product.DisplayName != "" {
+		return w.config.Epicshop.Product.DisplayName
+	}
+	return filepath.Base(w.Path)
 }
 
 func (w *Workshop) AsciiTitle() string {
@@ -51,12 +72,20 @@ func (w *Workshop) PlaygroundModTime() (*time.Time, error) {
 
 	var latestModTime time.Time
 
-	err := filepath.Walk(playgroundPath, func(path string, fileInfo fs.FileInfo, err error) error {
-		if err != nil || fileInfo == nil {
+	err := filepath.WalkDir(playgroundPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
 			return nil
 		}
 
-		if fileInfo.IsDir() {
+		if d.IsDir() {
+			if path != playgroundPath && IsDependencyOrCacheDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		fileInfo, err := d.Info()
+		if err != nil {
 			return nil
 		}
 
@@ -75,6 +104,16 @@ func (w *Workshop) PlaygroundModTime() (*time.Time, error) {
 	return &latestModTime, nil
 }
 
+// IsDependencyOrCacheDir reports whether a directory only holds installed dependencies, build
+// output or caches, whose modification times don't reflect the user's activity.
+func IsDependencyOrCacheDir(name string) bool {
+	switch name {
+	case "node_modules", ".git", ".cache", ".next", ".turbo", ".vite", ".parcel-cache", "dist", "build", "coverage":
+		return true
+	}
+	return false
+}
+
 func (w *Workshop) PlaygroundHash() (string, error) {
 	if !w.HasPlayground() {
 		return "", fmt.Errorf("workshop '%s' does not have a playground folder\n", w.Path)
@@ -82,18 +121,79 @@ func (w *Workshop) PlaygroundHash() (string, error) {
 	return HashFromPath(filepath.Join(w.Path, "playground"))
 }
 
+// Outline returns the full model of the workshop. It is loaded once and cached for the lifetime of the workshop.
+func (w *Workshop) Outline() (*Outline, error) {
+	if w.outline != nil {
+		return w.outline, nil
+	}
+
+	outline, err := LoadOutline(w)
+	if err != nil {
+		return nil, fmt.Errorf("loading workshop outline: %w", err)
+	}
+
+	w.outline = outline
+	return outline, nil
+}
+
+// exercisePaths returns the paths of the problem directories followed by the paths of the solution
+// directories, so problems take precedence when both have the same README.mdx.
+func (w *Workshop) exercisePaths() ([]string, error) {
+	outline, err := w.Outline()
+	if err != nil {
+		return nil, err
+	}
+
+	var problemPaths, solutionPaths []string
+	for _, exercise := range outline.Exercises() {
+		if exercise.ProblemPath != "" {
+			problemPaths = append(problemPaths, exercise.ProblemPath)
+		}
+		if exercise.SolutionPath != "" {
+			solutionPaths = append(solutionPaths, exercise.SolutionPath)
+		}
+	}
+
+	return append(problemPaths, solutionPaths...), nil
+}
+
+// Exercises returns all the exercises of the workshop, ordered by section and exercise number.
+func (w *Workshop) Exercises() ([]*Exercise, error) {
+	outline, err := w.Outline()
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := make([]*Exercise, 0, len(outline.Exercises()))
+	for _, exercise := range outline.Exercises() {
+		exercises = append(exercises, exercise.Exercise)
+	}
+
+	return exercises, nil
+}
+
 func (w *Workshop) LookupExerciseFromHash(targetHash string) (*Exercise, error) {
-	exercisePaths, err := filepath.Glob(filepath.Join(w.Path, "exercises", "*", "*.problem.*"))
+	exercisePaths, err := w.exercisePaths()
 	if err != nil {
-		return nil, fmt.Errorf("getting exercise paths: %w\n", err)
+		return nil, err
 	}
-	for _, path := range exercisePaths {
-		hash, err := HashFromPath(path)
-		if err != nil {
-			return nil, fmt.Errorf("getting hash for exercise at '%s': %w\n", path, err)
+	if w.index != nil {
+		// The index is only a cache, failing to persist it should not fail the lookup
+		defer w.index.Save()
+	}
+
+	hashes := make([]string, len(exercisePaths))
+	errs := make([]error, len(exercisePaths))
+	parallelFor(len(exercisePaths), func(i int) {
+		hashes[i], errs[i] = w.exerciseHash(exercisePaths[i])
+	})
+
+	for i, path := range exercisePaths {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("getting hash for exercise at '%s': %w\n", path, errs[i])
 		}
 
-		if hash == targetHash {
+		if hashes[i] == targetHash {
 			exercise, err := ExerciseFromPath(path)
 			if err != nil {
 				return nil, fmt.Errorf("getting exercise from path '%s': %w\n", path, err)
@@ -117,7 +217,7 @@ func (w *Workshop) PlaygroundExercise() (*Exercise, error) {
 	}
 
 	if exercise == nil {
-		return nil, fmt.Errorf("no exercise found for playground hash '%s'", playgroundHash)
+		return nil, fmt.Errorf("%w '%s'", ErrNoExactMatch, playgroundHash)
 	}
 
 	return exercise, nil
@@ -143,48 +243,52 @@ func WorkshopFromPath(workshopPath string) (*Workshop, error) {
 // DetectCurrentWorkshop automatically detects the current workshop from a directory
 // containing workshop sub-directories by finding the one with the most recent playground mod time
 func DetectCurrentWorkshop(workshopsDir string) (*Workshop, error) {
-	if !directory.Exists(workshopsDir) {
-		return nil, fmt.Errorf("workshops directory '%s' does not exist", workshopsDir)
-	}
+	return DetectCurrentWorkshopIn(SearchOptions{Roots: []string{workshopsDir}, Depth: 1})
+}
 
-	entries, err := os.ReadDir(workshopsDir)
+// DetectCurrentWorkshopIn detects the current workshop as the one with the most recent playground
+// mod time among all the workshops found with the search options.
+func DetectCurrentWorkshopIn(opts SearchOptions) (*Workshop, error) {
+	workshopPaths, err := FindWorkshops(opts)
 	if err != nil {
-		return nil, fmt.Errorf("reading workshops directory '%s': %w", workshopsDir, err)
+		return nil, err
 	}
 
-	var latestWorkshop *Workshop
-	var latestModTime time.Time
-	var foundWorkshop bool
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		workshopPath := filepath.Join(workshopsDir, entry.Name())
-		if !isWorkshopFolder(workshopPath) {
-			continue
-		}
-
-		workshop, err := WorkshopFromPath(workshopPath)
+	workshops := make([]*Workshop, len(workshopPaths))
+	modTimes := make([]*time.Time, len(workshopPaths))
+	parallelFor(len(workshopPaths), func(i int) {
+		workshop, err := WorkshopFromPath(workshopPaths[i])
 		if err != nil {
-			continue // Skip workshops that can't be loaded
+			return // Skip workshops that can't be loaded
 		}
 
 		modTime, err := workshop.PlaygroundModTime()
 		if err != nil {
-			continue // Skip workshops without playground or with errors
+			return // Skip workshops without playground or with errors
 		}
 
-		if modTime.After(latestModTime) {
-			latestModTime = *modTime
+		workshops[i] = workshop
+		modTimes[i] = modTime
+	})
+
+	var latestWorkshop *Workshop
+	var latestModTime time.Time
+	var foundWorkshop bool
+
+	for i, workshop := range workshops {
+		if workshop == nil {
+			continue
+		}
+
+		if modTimes[i].After(latestModTime) {
+			latestModTime = *modTimes[i]
 			latestWorkshop = workshop
 			foundWorkshop = true
 		}
 	}
 
 	if !foundWorkshop {
-		return nil, fmt.Errorf("no valid workshops found in directory '%s'", workshopsDir)
+		return nil, fmt.Errorf("no valid workshops found in directories '%s'", opts)
 	}
 
 	return latestWorkshop, nil
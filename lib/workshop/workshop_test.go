@@ -0,0 +1,109 @@
+package workshop
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	benchWorkshops      = 8
+	benchPlaygroundSrc  = 20
+	benchNodeModules    = 300
+	benchFilesPerModule = 5
+)
+
+// writeBenchWorkshops generates workshops under root whose playgrounds have a few source files and a
+// large node_modules, like the playgrounds of real workshops after npm install.
+func writeBenchWorkshops(tb testing.TB, root string) {
+	tb.Helper()
+
+	write := func(path string, contents string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	for i := range benchWorkshops {
+		workshopPath := filepath.Join(root, fmt.Sprintf("workshop-%02d", i))
+		write(filepath.Join(workshopPath, "package.json"), fmt.Sprintf(`{"name":"workshop-%02d","epicshop":{"title":"Workshop %02d"}}`, i, i))
+		write(filepath.Join(workshopPath, "epicshop", "package.json"), "{}")
+		write(filepath.Join(workshopPath, "exercises", "01.intro", "01.problem.start", "README.mdx"), "# Start\n")
+
+		playground := filepath.Join(workshopPath, "playground")
+		for f := range benchPlaygroundSrc {
+			write(filepath.Join(playground, "src", fmt.Sprintf("file-%02d.tsx", f)), "export {}\n")
+		}
+		for m := range benchNodeModules {
+			for f := range benchFilesPerModule {
+				write(filepath.Join(playground, "node_modules", fmt.Sprintf("module-%03d", m), fmt.Sprintf("index-%d.js", f)), "module.exports = {}\n")
+			}
+		}
+	}
+}
+
+// playgroundModTimeWalkAll is PlaygroundModTime without skipping the dependency and cache directories.
+func playgroundModTimeWalkAll(w *Workshop) (*time.Time, error) {
+	var latestModTime time.Time
+	err := filepath.WalkDir(w.PlaygroundPath(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(latestModTime) {
+			latestModTime = info.ModTime()
+		}
+		return nil
+	})
+	return &latestModTime, err
+}
+
+func BenchmarkPlaygroundModTime(b *testing.B) {
+	root := b.TempDir()
+	writeBenchWorkshops(b, root)
+
+	w, err := WorkshopFromPath(filepath.Join(root, "workshop-00"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("walk-all", func(b *testing.B) {
+		for range b.N {
+			if _, err := playgroundModTimeWalkAll(w); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("skip-dependencies", func(b *testing.B) {
+		for range b.N {
+			if _, err := w.PlaygroundModTime(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkDetectCurrentWorkshopIn reads the playgrounds of the workshops with GOMAXPROCS workers, run
+// it with -cpu 1,4 to compare detecting the workshop sequentially and in parallel.
+func BenchmarkDetectCurrentWorkshopIn(b *testing.B) {
+	root := b.TempDir()
+	writeBenchWorkshops(b, root)
+	opts := SearchOptions{Roots: []string{root}, Depth: 1}
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := DetectCurrentWorkshopIn(opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}